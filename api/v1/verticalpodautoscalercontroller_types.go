@@ -17,7 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -49,6 +51,14 @@ type DeploymentOverride struct {
 	// +optional
 	Container ContainerOverride `json:"container"`
 
+	// Replicas is the number of pods of the deployment, which defaults to 1. With more than one
+	// replica the pods are spread across nodes unless an affinity is set, the recommender and
+	// updater elect a leader with the extra replicas on hot standby, and the admission controller
+	// serves the webhook from all replicas.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
 	// Override the NodeSelector of the deployment's pod. This allows, for example, for the VPA controllers
 	// to be run on non-master nodes
 	// +optional
@@ -58,6 +68,36 @@ type DeploymentOverride struct {
 	// to be run on non-master nodes with a specific taint
 	// +optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Override the Affinity of the deployment's pod, e.g. to spread the VPA controllers away from
+	// other workloads with pod anti-affinity
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Override the TopologySpreadConstraints of the deployment's pod
+	// +listType=atomic
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// Override the PriorityClassName of the deployment's pod, which is system-cluster-critical by default
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Labels are added to the labels of the deployment's pod, e.g. to opt out of a service mesh.
+	// The labels set by the operator cannot be overridden.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to the annotations of the deployment's pod, e.g. for cost allocation.
+	// The annotations set by the operator cannot be overridden.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// ImagePullSecrets are added to the deployment's pod, e.g. to pull an overridden image from
+	// a private mirror
+	// +listType=atomic
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
 }
 
 // ContainerOverride defines fields that can be overridden for a given container
@@ -66,14 +106,284 @@ type ContainerOverride struct {
 	// to keep the fields equivalent. I'd just make this a corev1.Container, but we'd have to
 	// silently drop fields we don't support and I'd rather not be confusing
 
-	// args is a list of args that will be appended to the container spec
+	// args is a list of args that are merged into the args set by the operator.
+	// A flag the operator already sets is replaced, a flag ending in a dash
+	// (e.g. --memory-saver-) removes it, and any other flag is appended.
 	// +optional
 	Args []string `json:"args,omitempty"`
 	// resources is a set of resource requirements that will replace existing container resource requirements
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// env is a list of environment variables that will be added to the container, e.g. GOMEMLIMIT
+	// or HTTPS_PROXY
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// image replaces the operand image shipped with the operator, e.g. to canary a patched build
+	// or pull from a private mirror. The operand is not reported at the release version while it
+	// runs an image other than the operator's.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// imagePullPolicy replaces the pull policy of the container, which is Always by default
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+}
+
+// LogFormat is the format of the logs of one of the VPA's controllers.
+// +kubebuilder:validation:Enum=Text;JSON
+type LogFormat string
+
+const (
+	// LogFormatText makes a controller log human-readable lines, the controllers' default
+	LogFormatText LogFormat = "Text"
+	// LogFormatJSON makes a controller log structured JSON objects
+	LogFormatJSON LogFormat = "JSON"
+)
+
+// LoggingConfig defines the logging of one of the VPA's controllers.
+type LoggingConfig struct {
+	// verbosity is the log verbosity of the controller. Defaults to the verbosity the
+	// operator was started with.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	Verbosity *int32 `json:"verbosity,omitempty"`
+	// vmodule sets the verbosity of individual source files, as a comma-separated list of
+	// pattern=N settings matched against file names without their extension, e.g.
+	// "updater=4,eviction*=6".
+	// +kubebuilder:validation:MaxLength=1024
+	// +optional
+	VModule string `json:"vmodule,omitempty"`
+	// format is the format of the logs of the controller, Text or JSON. Defaults to Text.
+	// +optional
+	Format LogFormat `json:"format,omitempty"`
+}
+
+// RecommenderConfig defines the tuning of the VPA's recommender. Unset fields are left
+// at the recommender's own defaults.
+type RecommenderConfig struct {
+	// cpuHistogramDecayHalfLife is the amount of time it takes a historical CPU usage
+	// sample to lose half of its weight. The recommender defaults to 24h.
+	// +optional
+	CPUHistogramDecayHalfLife *metav1.Duration `json:"cpuHistogramDecayHalfLife,omitempty"`
+	// memoryHistogramDecayHalfLife is the amount of time it takes a historical memory
+	// usage sample to lose half of its weight. The recommender defaults to 24h.
+	// +optional
+	MemoryHistogramDecayHalfLife *metav1.Duration `json:"memoryHistogramDecayHalfLife,omitempty"`
+	// targetCPUPercentile is the usage percentile used as the base for the CPU target
+	// recommendation. The recommender defaults to 0.9.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	TargetCPUPercentile *float64 `json:"targetCPUPercentile,omitempty"`
+	// targetMemoryPercentile is the usage percentile used as the base for the memory
+	// target recommendation. The recommender defaults to 0.9.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	TargetMemoryPercentile *float64 `json:"targetMemoryPercentile,omitempty"`
+	// oomBumpUpRatio is the ratio by which the memory recommendation is increased
+	// after a container is OOM killed. The recommender defaults to 1.2.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	OOMBumpUpRatio *float64 `json:"oomBumpUpRatio,omitempty"`
+	// oomMinBumpUp is the minimum amount by which the memory recommendation is
+	// increased after a container is OOM killed. The recommender defaults to 100Mi.
+	// +optional
+	OOMMinBumpUp *resource.Quantity `json:"oomMinBumpUp,omitempty"`
+	// recommenderInterval is how often the recommender fetches metrics and computes
+	// recommendations. The recommender defaults to 1m.
+	// +optional
+	RecommenderInterval *metav1.Duration `json:"recommenderInterval,omitempty"`
+	// checkpointsGCInterval is how often the recommender garbage collects stale
+	// VerticalPodAutoscalerCheckpoints. The recommender defaults to 10m.
+	// +optional
+	CheckpointsGCInterval *metav1.Duration `json:"checkpointsGCInterval,omitempty"`
+	// memorySaver makes the recommender only track pods that have a matching
+	// VerticalPodAutoscaler, which reduces its memory usage in large clusters.
+	// +optional
+	MemorySaver *bool `json:"memorySaver,omitempty"`
+	// history makes the recommender load the usage history of containers from Prometheus
+	// when it starts, so its recommendations do not start from empty histograms. By
+	// default the recommender only keeps the history it saved in checkpoints.
+	// +optional
+	History *RecommenderHistory `json:"history,omitempty"`
+	// featureGates enables or disables feature gates of the recommender, e.g. InPlaceOrRecreate.
+	// Only the gates of the VPA version shipped with the operator are accepted, unless the
+	// image of the recommender is overridden.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// logging configures the logs of the recommender. Changing it only rolls out the recommender.
+	// +optional
+	Logging *LoggingConfig `json:"logging,omitempty"`
+}
+
+// RecommenderHistory defines where the recommender finds the usage history of containers
+// in Prometheus and how the labels of the cAdvisor metrics map to namespaces, pods and
+// containers. Unset fields default to the OpenShift cluster monitoring stack.
+type RecommenderHistory struct {
+	// address is the URL of the Prometheus API. The recommender authenticates with the
+	// token of its service account and trusts the service CA. Defaults to
+	// https://thanos-querier.openshift-monitoring.svc:9091.
+	// +kubebuilder:validation:MaxLength=2048
+	// +optional
+	Address string `json:"address,omitempty"`
+	// jobName is the job label of the cAdvisor metrics. Defaults to kubelet.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_:./-]+$`
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+	// namespaceLabel is the label holding the namespace of a pod or container. Defaults
+	// to namespace.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	NamespaceLabel string `json:"namespaceLabel,omitempty"`
+	// podLabel is the label holding the name of a pod. Defaults to pod.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	PodLabel string `json:"podLabel,omitempty"`
+	// containerLabel is the label holding the name of a container. Defaults to container.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	ContainerLabel string `json:"containerLabel,omitempty"`
+}
+
+// UpdaterConfig defines how aggressively the VPA's updater evicts pods. Unset fields are
+// left at the updater's own defaults.
+type UpdaterConfig struct {
+	// enabled runs the updater, which evicts pods to apply new recommendations. Defaults to
+	// true unless recommendationOnly is set. Disabling it while the admission controller
+	// stays enabled only applies recommendations when pods are created.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// evictionTolerance is the fraction of a workload's replicas that may be evicted
+	// for an update at the same time. The updater defaults to 0.5.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	EvictionTolerance *float64 `json:"evictionTolerance,omitempty"`
+	// evictionRateLimit is the number of pods that may be evicted per second across
+	// the cluster. By default the updater does not limit the eviction rate.
+	// +optional
+	EvictionRateLimit *float64 `json:"evictionRateLimit,omitempty"`
+	// evictionRateBurst is the number of pods that may be evicted in a burst when
+	// evictionRateLimit is set. The updater defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	EvictionRateBurst *int32 `json:"evictionRateBurst,omitempty"`
+	// updaterInterval is how often the updater checks whether pods need to be
+	// evicted. The updater defaults to 1m.
+	// +optional
+	UpdaterInterval *metav1.Duration `json:"updaterInterval,omitempty"`
+	// evictAfterOOMThreshold is the time since a pod started within which it is
+	// evicted after being OOM killed, so it is recreated with the bumped up memory
+	// recommendation. The updater defaults to 10m.
+	// +optional
+	EvictAfterOOMThreshold *metav1.Duration `json:"evictAfterOOMThreshold,omitempty"`
+	// inRecommendationBoundsEvictionLifetimeThreshold is how long a pod whose
+	// requests are within the recommended bounds must have run before it can be
+	// evicted to apply a new recommendation. The updater defaults to 12h.
+	// +optional
+	InRecommendationBoundsEvictionLifetimeThreshold *metav1.Duration `json:"inRecommendationBoundsEvictionLifetimeThreshold,omitempty"`
+	// featureGates enables or disables feature gates of the updater, e.g. InPlaceOrRecreate.
+	// Only the gates of the VPA version shipped with the operator are accepted, unless the
+	// image of the updater is overridden.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// logging configures the logs of the updater. Changing it only rolls out the updater.
+	// +optional
+	Logging *LoggingConfig `json:"logging,omitempty"`
+}
+
+// AdmissionConfig defines the configuration of the VPA's admission controller and of
+// the mutating webhook it registers. Unset fields are left at the operator's defaults.
+type AdmissionConfig struct {
+	// enabled runs the admission controller, which applies recommendations to pods when
+	// they are created. Defaults to true unless recommendationOnly is set.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// webhookTimeoutSeconds is how long the API server waits for the admission
+	// controller's webhook before applying the failure policy. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=30
+	// +optional
+	WebhookTimeoutSeconds *int32 `json:"webhookTimeoutSeconds,omitempty"`
+	// failurePolicy is what the API server does with a pod when the admission
+	// controller's webhook cannot be reached. Defaults to Ignore.
+	// +kubebuilder:validation:Enum=Ignore;Fail
+	// +optional
+	FailurePolicy *admissionregistrationv1.FailurePolicyType `json:"failurePolicy,omitempty"`
+	// kubeAPIQPS is the queries per second the admission controller may send to
+	// the API server. Defaults to 25.
+	// +optional
+	KubeAPIQPS *float64 `json:"kubeAPIQPS,omitempty"`
+	// kubeAPIBurst is the number of queries the admission controller may send to
+	// the API server in a burst. Defaults to 50.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KubeAPIBurst *int32 `json:"kubeAPIBurst,omitempty"`
+	// ignoredNamespaces are namespaces whose pods are excluded from the admission
+	// controller's webhook and whose VerticalPodAutoscalers are ignored by it.
+	// +listType=set
+	// +optional
+	IgnoredNamespaces []string `json:"ignoredNamespaces,omitempty"`
+	// featureGates enables or disables feature gates of the admission controller, e.g. InPlaceOrRecreate.
+	// Only the gates of the VPA version shipped with the operator are accepted, unless the
+	// image of the admission controller is overridden.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// logging configures the logs of the admission controller. Changing it only rolls out
+	// the admission controller.
+	// +optional
+	Logging *LoggingConfig `json:"logging,omitempty"`
 }
 
+// NamedRecommender defines an alternative recommender, which VerticalPodAutoscalers select by
+// name in their spec.recommenders
+type NamedRecommender struct {
+	// name is the name VerticalPodAutoscalers select the recommender by. The default
+	// recommender is named "default".
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=47
+	Name string `json:"name"`
+	// config is the typed configuration of the recommender. The top-level recommender
+	// block only applies to the default recommender and is not inherited.
+	// +optional
+	Config *RecommenderConfig `json:"config,omitempty"`
+	// deploymentOverride is the deployment overrides for the recommender's container
+	// +optional
+	DeploymentOverride DeploymentOverride `json:"deploymentOverride"`
+}
+
+// PodDisruptionBudgetsPolicy is whether the operator manages a PodDisruptionBudget for
+// each of the VPA's operands
+// +kubebuilder:validation:Enum=Enabled;Disabled
+type PodDisruptionBudgetsPolicy string
+
+const (
+	// PodDisruptionBudgetsEnabled makes the operator manage a PodDisruptionBudget for each operand
+	PodDisruptionBudgetsEnabled PodDisruptionBudgetsPolicy = "Enabled"
+	// PodDisruptionBudgetsDisabled makes the operator remove the PodDisruptionBudgets it created
+	PodDisruptionBudgetsDisabled PodDisruptionBudgetsPolicy = "Disabled"
+)
+
+// PruningPolicy is whether the operator deletes the objects it manages that it no longer
+// renders
+// +kubebuilder:validation:Enum=Enabled;DryRun
+type PruningPolicy string
+
+const (
+	// PruningEnabled makes the operator delete the objects it no longer renders
+	PruningEnabled PruningPolicy = "Enabled"
+	// PruningDryRun makes the operator only report the objects it would delete in the status
+	PruningDryRun PruningPolicy = "DryRun"
+)
+
 // VerticalPodAutoscalerControllerSpec defines the desired state of VerticalPodAutoscalerController
 type VerticalPodAutoscalerControllerSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Safety Margin Fraction",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
@@ -84,19 +394,185 @@ type VerticalPodAutoscalerControllerSpec struct {
 	PodMinCPUMillicores *float64 `json:"podMinCPUMillicores,omitempty"`
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pod Minimum Memory (MB)",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
 	// +kubebuilder:validation:Minimum=0
-	PodMinMemoryMb     *float64 `json:"podMinMemoryMb,omitempty"`
-	RecommendationOnly *bool    `json:"recommendationOnly,omitempty"`
+	PodMinMemoryMb *float64 `json:"podMinMemoryMb,omitempty"`
+	// recommendationOnly disables both the updater and the admission controller, so
+	// recommendations are only reported in the status of VerticalPodAutoscalers. It is a
+	// shorthand for setting enabled to false in the updater and admission blocks.
+	RecommendationOnly *bool `json:"recommendationOnly,omitempty"`
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Minimum Replicas",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
 	// +kubebuilder:validation:Minimum=1
 	MinReplicas *int64 `json:"minReplicas,omitempty"`
+	// recommender is the typed configuration of the VPA's recommender
+	// +optional
+	Recommender *RecommenderConfig `json:"recommender,omitempty"`
+	// recommenders are alternative recommenders run next to the default one, each in its
+	// own deployment
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Recommenders []NamedRecommender `json:"recommenders,omitempty"`
+	// ignoredNamespaces are namespaces the recommenders, updater and admission controller
+	// do not act on, on top of the platform namespaces (default, openshift and those
+	// prefixed with kube- or openshift-) and the operator's namespace, which are always
+	// ignored.
+	// +listType=set
+	// +kubebuilder:validation:items:MaxLength=63
+	// +optional
+	IgnoredNamespaces []string `json:"ignoredNamespaces,omitempty"`
+	// namespaceSelector restricts the webhook of the admission controller to the namespaces
+	// whose labels match it. The recommenders can only ignore namespaces by name, so they
+	// still act on the namespaces it does not select, and it may only be set while the
+	// updater is disabled, which would otherwise evict pods the webhook does not update.
+	// The ignored namespaces are excluded even if they match.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// updater is the typed configuration of the VPA's updater
+	// +optional
+	Updater *UpdaterConfig `json:"updater,omitempty"`
+	// admission is the typed configuration of the VPA's admission controller
+	// +optional
+	Admission *AdmissionConfig `json:"admission,omitempty"`
+	// podDisruptionBudgets is whether the operator manages a PodDisruptionBudget for each
+	// operand, which allows all but one of its replicas to be disrupted at the same time.
+	// Defaults to Enabled.
+	// +optional
+	PodDisruptionBudgets PodDisruptionBudgetsPolicy `json:"podDisruptionBudgets,omitempty"`
+	// pruning is whether the operator deletes the deployments, services, ConfigMaps,
+	// NetworkPolicies, PodDisruptionBudgets, Roles and RoleBindings it manages once it no
+	// longer renders them, e.g. those of a disabled operand or left behind by an older
+	// release. DryRun only reports them in status.staleObjects. Defaults to Enabled.
+	// +optional
+	Pruning PruningPolicy `json:"pruning,omitempty"`
 	//
 	// +optional
 	DeploymentOverrides DeploymentOverrides `json:"deploymentOverrides"`
 }
 
+// Condition types reported in the status of a VerticalPodAutoscalerController and its operands.
+const (
+	// ConditionAvailable indicates that the operand deployments have available replicas.
+	ConditionAvailable = "Available"
+	// ConditionProgressing indicates that an operand deployment is rolling out a change.
+	ConditionProgressing = "Progressing"
+	// ConditionDegraded indicates that the operator failed to reconcile an operand, or that
+	// an operand deployment is failing.
+	ConditionDegraded = "Degraded"
+	// ConditionExtraArgsValid indicates whether the extra arguments the operator was started
+	// with could be parsed.  It is only reported while the operator has extra arguments.
+	ConditionExtraArgsValid = "ExtraArgsValid"
+	// ConditionDebugSession indicates whether a debug session raises the verbosity of an
+	// operand.  It is only reported while the VerticalPodAutoscalerController has a
+	// debug session annotation.
+	ConditionDebugSession = "DebugSession"
+	// ConditionReconcileFailedPrefix prefixes the type of the condition reported for each
+	// resource the operator failed to reconcile in its last pass, followed by the
+	// lowercase kind and the name of the resource, e.g.
+	// "ReconcileFailed.networkpolicy.vpa-default-deny".  The characters of the name
+	// that a condition type does not allow are replaced by underscores.
+	ConditionReconcileFailedPrefix = "ReconcileFailed."
+)
+
+// OperandFailure describes the most recent failure observed for an operand
+type OperandFailure struct {
+	// reason is a machine-readable CamelCase reason for the failure
+	Reason string `json:"reason"`
+	// message is a human-readable description of the failure
+	// +optional
+	Message string `json:"message,omitempty"`
+	// time is when the failure was first observed
+	Time metav1.Time `json:"time"`
+}
+
+// OperandStatus defines the observed state of one of the VPA's operand deployments
+type OperandStatus struct {
+	// conditions are the Available, Progressing and Degraded conditions of the operand
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// image is the operand image that was last fully rolled out
+	// +optional
+	Image string `json:"image,omitempty"`
+	// releaseVersion is the release version of the operand that was last fully rolled out. It is
+	// empty while the operand runs an image other than the operator's.
+	// +optional
+	ReleaseVersion string `json:"releaseVersion,omitempty"`
+	// featureGates are the feature gates enabled in the operand that was last fully rolled out
+	// +listType=set
+	// +optional
+	FeatureGates []string `json:"featureGates,omitempty"`
+	// lastFailure is the most recent failure observed for the operand. It is retained after
+	// the operand recovers, so it can be used to see what went wrong last.
+	// +optional
+	LastFailure *OperandFailure `json:"lastFailure,omitempty"`
+}
+
+// NamedOperandStatus defines the observed state of one of the alternative recommenders
+type NamedOperandStatus struct {
+	// name is the name of the recommender
+	Name string `json:"name"`
+
+	OperandStatus `json:",inline"`
+}
+
 // VerticalPodAutoscalerControllerStatus defines the observed state of VerticalPodAutoscalerController
 type VerticalPodAutoscalerControllerStatus struct {
-	// TODO: Add status fields.
+	// observedGeneration is the most recent generation of the spec observed by the operator
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// conditions summarize the Available, Progressing and Degraded conditions of all operands
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// admission is the observed state of the VPA's admission controller
+	// +optional
+	Admission OperandStatus `json:"admission,omitempty"`
+	// recommender is the observed state of the VPA's recommender
+	// +optional
+	Recommender OperandStatus `json:"recommender,omitempty"`
+	// updater is the observed state of the VPA's updater
+	// +optional
+	Updater OperandStatus `json:"updater,omitempty"`
+	// recommenders are the observed states of the alternative recommenders
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Recommenders []NamedOperandStatus `json:"recommenders,omitempty"`
+	// coveredNamespaceCount is the number of existing namespaces the recommenders and
+	// updater act on, as of the last reconcile. Only platform namespaces being created or
+	// deleted trigger a reconcile, so it can lag behind other namespaces.
+	// +optional
+	CoveredNamespaceCount int32 `json:"coveredNamespaceCount,omitempty"`
+	// ignoredNamespaces are the namespaces the recommenders and updater are configured to
+	// ignore, including the platform namespaces. The admission controller further ignores
+	// its own admission.ignoredNamespaces.
+	// +listType=set
+	// +optional
+	IgnoredNamespaces []string `json:"ignoredNamespaces,omitempty"`
+	// staleObjects are the objects the operator no longer renders but leaves in place
+	// while pruning is DryRun, as kind: name
+	// +listType=set
+	// +optional
+	StaleObjects []string `json:"staleObjects,omitempty"`
+}
+
+// UpdaterEnabled returns whether the updater runs, which is the case unless it
+// is disabled itself or by recommendationOnly.
+func (s *VerticalPodAutoscalerControllerSpec) UpdaterEnabled() bool {
+	if s.Updater != nil && s.Updater.Enabled != nil {
+		return *s.Updater.Enabled
+	}
+	return s.RecommendationOnly == nil || !*s.RecommendationOnly
+}
+
+// AdmissionEnabled returns whether the admission controller runs, which is the
+// case unless it is disabled itself or by recommendationOnly.
+func (s *VerticalPodAutoscalerControllerSpec) AdmissionEnabled() bool {
+	if s.Admission != nil && s.Admission.Enabled != nil {
+		return *s.Admission.Enabled
+	}
+	return s.RecommendationOnly == nil || !*s.RecommendationOnly
 }
 
 // +kubebuilder:object:root=true
@@ -21,10 +21,69 @@ limitations under the License.
 package v1
 
 import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdmissionConfig) DeepCopyInto(out *AdmissionConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WebhookTimeoutSeconds != nil {
+		in, out := &in.WebhookTimeoutSeconds, &out.WebhookTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailurePolicy != nil {
+		in, out := &in.FailurePolicy, &out.FailurePolicy
+		*out = new(admissionregistrationv1.FailurePolicyType)
+		**out = **in
+	}
+	if in.KubeAPIQPS != nil {
+		in, out := &in.KubeAPIQPS, &out.KubeAPIQPS
+		*out = new(float64)
+		**out = **in
+	}
+	if in.KubeAPIBurst != nil {
+		in, out := &in.KubeAPIBurst, &out.KubeAPIBurst
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IgnoredNamespaces != nil {
+		in, out := &in.IgnoredNamespaces, &out.IgnoredNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdmissionConfig.
+func (in *AdmissionConfig) DeepCopy() *AdmissionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AdmissionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerOverride) DeepCopyInto(out *ContainerOverride) {
 	*out = *in
@@ -34,6 +93,13 @@ func (in *ContainerOverride) DeepCopyInto(out *ContainerOverride) {
 		copy(*out, *in)
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerOverride.
@@ -50,6 +116,11 @@ func (in *ContainerOverride) DeepCopy() *ContainerOverride {
 func (in *DeploymentOverride) DeepCopyInto(out *DeploymentOverride) {
 	*out = *in
 	in.Container.DeepCopyInto(&out.Container)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
 		*out = make(map[string]string, len(*in))
@@ -64,6 +135,37 @@ func (in *DeploymentOverride) DeepCopyInto(out *DeploymentOverride) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentOverride.
@@ -94,13 +196,272 @@ func (in *DeploymentOverrides) DeepCopy() *DeploymentOverrides {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoggingConfig) DeepCopyInto(out *LoggingConfig) {
+	*out = *in
+	if in.Verbosity != nil {
+		in, out := &in.Verbosity, &out.Verbosity
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoggingConfig.
+func (in *LoggingConfig) DeepCopy() *LoggingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LoggingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamedOperandStatus) DeepCopyInto(out *NamedOperandStatus) {
+	*out = *in
+	in.OperandStatus.DeepCopyInto(&out.OperandStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamedOperandStatus.
+func (in *NamedOperandStatus) DeepCopy() *NamedOperandStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NamedOperandStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamedRecommender) DeepCopyInto(out *NamedRecommender) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(RecommenderConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	in.DeploymentOverride.DeepCopyInto(&out.DeploymentOverride)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamedRecommender.
+func (in *NamedRecommender) DeepCopy() *NamedRecommender {
+	if in == nil {
+		return nil
+	}
+	out := new(NamedRecommender)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandFailure) DeepCopyInto(out *OperandFailure) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandFailure.
+func (in *OperandFailure) DeepCopy() *OperandFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandStatus) DeepCopyInto(out *OperandStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastFailure != nil {
+		in, out := &in.LastFailure, &out.LastFailure
+		*out = new(OperandFailure)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandStatus.
+func (in *OperandStatus) DeepCopy() *OperandStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecommenderConfig) DeepCopyInto(out *RecommenderConfig) {
+	*out = *in
+	if in.CPUHistogramDecayHalfLife != nil {
+		in, out := &in.CPUHistogramDecayHalfLife, &out.CPUHistogramDecayHalfLife
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MemoryHistogramDecayHalfLife != nil {
+		in, out := &in.MemoryHistogramDecayHalfLife, &out.MemoryHistogramDecayHalfLife
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TargetCPUPercentile != nil {
+		in, out := &in.TargetCPUPercentile, &out.TargetCPUPercentile
+		*out = new(float64)
+		**out = **in
+	}
+	if in.TargetMemoryPercentile != nil {
+		in, out := &in.TargetMemoryPercentile, &out.TargetMemoryPercentile
+		*out = new(float64)
+		**out = **in
+	}
+	if in.OOMBumpUpRatio != nil {
+		in, out := &in.OOMBumpUpRatio, &out.OOMBumpUpRatio
+		*out = new(float64)
+		**out = **in
+	}
+	if in.OOMMinBumpUp != nil {
+		in, out := &in.OOMMinBumpUp, &out.OOMMinBumpUp
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.RecommenderInterval != nil {
+		in, out := &in.RecommenderInterval, &out.RecommenderInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CheckpointsGCInterval != nil {
+		in, out := &in.CheckpointsGCInterval, &out.CheckpointsGCInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MemorySaver != nil {
+		in, out := &in.MemorySaver, &out.MemorySaver
+		*out = new(bool)
+		**out = **in
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = new(RecommenderHistory)
+		**out = **in
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecommenderConfig.
+func (in *RecommenderConfig) DeepCopy() *RecommenderConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RecommenderConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecommenderHistory) DeepCopyInto(out *RecommenderHistory) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecommenderHistory.
+func (in *RecommenderHistory) DeepCopy() *RecommenderHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(RecommenderHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdaterConfig) DeepCopyInto(out *UpdaterConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EvictionTolerance != nil {
+		in, out := &in.EvictionTolerance, &out.EvictionTolerance
+		*out = new(float64)
+		**out = **in
+	}
+	if in.EvictionRateLimit != nil {
+		in, out := &in.EvictionRateLimit, &out.EvictionRateLimit
+		*out = new(float64)
+		**out = **in
+	}
+	if in.EvictionRateBurst != nil {
+		in, out := &in.EvictionRateBurst, &out.EvictionRateBurst
+		*out = new(int32)
+		**out = **in
+	}
+	if in.UpdaterInterval != nil {
+		in, out := &in.UpdaterInterval, &out.UpdaterInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.EvictAfterOOMThreshold != nil {
+		in, out := &in.EvictAfterOOMThreshold, &out.EvictAfterOOMThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.InRecommendationBoundsEvictionLifetimeThreshold != nil {
+		in, out := &in.InRecommendationBoundsEvictionLifetimeThreshold, &out.InRecommendationBoundsEvictionLifetimeThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(LoggingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdaterConfig.
+func (in *UpdaterConfig) DeepCopy() *UpdaterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdaterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VerticalPodAutoscalerController) DeepCopyInto(out *VerticalPodAutoscalerController) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticalPodAutoscalerController.
@@ -181,6 +542,38 @@ func (in *VerticalPodAutoscalerControllerSpec) DeepCopyInto(out *VerticalPodAuto
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Recommender != nil {
+		in, out := &in.Recommender, &out.Recommender
+		*out = new(RecommenderConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Recommenders != nil {
+		in, out := &in.Recommenders, &out.Recommenders
+		*out = make([]NamedRecommender, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IgnoredNamespaces != nil {
+		in, out := &in.IgnoredNamespaces, &out.IgnoredNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Updater != nil {
+		in, out := &in.Updater, &out.Updater
+		*out = new(UpdaterConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Admission != nil {
+		in, out := &in.Admission, &out.Admission
+		*out = new(AdmissionConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	in.DeploymentOverrides.DeepCopyInto(&out.DeploymentOverrides)
 }
 
@@ -197,6 +590,33 @@ func (in *VerticalPodAutoscalerControllerSpec) DeepCopy() *VerticalPodAutoscaler
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VerticalPodAutoscalerControllerStatus) DeepCopyInto(out *VerticalPodAutoscalerControllerStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Admission.DeepCopyInto(&out.Admission)
+	in.Recommender.DeepCopyInto(&out.Recommender)
+	in.Updater.DeepCopyInto(&out.Updater)
+	if in.Recommenders != nil {
+		in, out := &in.Recommenders, &out.Recommenders
+		*out = make([]NamedOperandStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IgnoredNamespaces != nil {
+		in, out := &in.IgnoredNamespaces, &out.IgnoredNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StaleObjects != nil {
+		in, out := &in.StaleObjects, &out.StaleObjects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticalPodAutoscalerControllerStatus.
@@ -44,6 +44,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	autoscalingv1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
 	// +kubebuilder:scaffold:imports
@@ -168,9 +169,17 @@ func main() {
 
 	config := operator.ConfigFromEnvironment()
 
+	// The webhook server uses the same TLS settings as the metrics server. Its
+	// serving certificate is mounted at the default location, either by OLM or
+	// from the service CA signed secret of the webhook service.
+	webhookServer := webhook.NewServer(webhook.Options{
+		TLSOpts: tlsOpts,
+	})
+
 	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
+		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "201e3e80.openshift.io",
@@ -210,27 +219,65 @@ func main() {
 		tlsProfilePointer = nil
 	}
 
+	// The reconciler and the webhooks share the config, so the validating
+	// webhook checks overrides against the arguments the reconciler sets.
+	vpaConfig := &verticalpodautoscaler.Config{
+		ReleaseVersion:         config.ReleaseVersion,
+		Name:                   config.VerticalPodAutoscalerName,
+		Image:                  config.VerticalPodAutoscalerImage,
+		Namespace:              config.VerticalPodAutoscalerNamespace,
+		Verbosity:              config.VerticalPodAutoscalerVerbosity,
+		ExtraArgs:              config.VerticalPodAutoscalerExtraArgs,
+		TLSProfileSpec:         tlsProfilePointer,
+		IsExternalControlPlane: isExternalControlPlane,
+	}
+
 	if err = (&verticalpodautoscaler.VerticalPodAutoscalerControllerReconciler{
 		Client:   mgr.GetClient(),
 		Log:      ctrl.Log.WithName("controllers").WithName("VerticalPodAutoscalerController"),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorder(verticalpodautoscaler.ControllerName),
 		Cache:    mgr.GetCache(),
-		Config: &verticalpodautoscaler.Config{
-			ReleaseVersion:         config.ReleaseVersion,
-			Name:                   config.VerticalPodAutoscalerName,
-			Image:                  config.VerticalPodAutoscalerImage,
-			Namespace:              config.VerticalPodAutoscalerNamespace,
-			Verbosity:              config.VerticalPodAutoscalerVerbosity,
-			ExtraArgs:              config.VerticalPodAutoscalerExtraArgs,
-			TLSProfileSpec:         tlsProfilePointer,
-			IsExternalControlPlane: isExternalControlPlane,
-		},
+		Config:   vpaConfig,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "VerticalPodAutoscalerController")
 		os.Exit(1)
 	}
 
+	if config.EnableWebhooks {
+		if err = verticalpodautoscaler.SetupWebhookWithManager(mgr, vpaConfig); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "VerticalPodAutoscalerController")
+			os.Exit(1)
+		}
+	}
+
+	// The status reporter is leader-election aware, so only the leader
+	// reports the operator's status via its ClusterOperator.
+	statusReporter, err := operator.NewStatusReporter(mgr, &operator.StatusReporterConfig{
+		VerticalPodAutoscalerName:      config.VerticalPodAutoscalerName,
+		VerticalPodAutoscalerNamespace: config.VerticalPodAutoscalerNamespace,
+		ReleaseVersion:                 config.ReleaseVersion,
+		RelatedObjects: []configv1.ObjectReference{
+			{
+				Resource: "namespaces",
+				Name:     config.VerticalPodAutoscalerNamespace,
+			},
+			{
+				Group:     autoscalingv1.GroupVersion.Group,
+				Resource:  "verticalpodautoscalercontrollers",
+				Namespace: config.WatchNamespace,
+			},
+		},
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to create status reporter")
+		os.Exit(1)
+	}
+	if err := mgr.Add(statusReporter); err != nil {
+		setupLog.Error(err, "unable to add status reporter to manager")
+		os.Exit(1)
+	}
+
 	// When secure metrics are enabled, the metrics server uses the centralized cluster TLS profile.
 	// If that profile changes, the operator exits so that the new profile will take effect after it is restarted
 	if secureMetrics {
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+
 	v1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
 	"github.com/openshift/vertical-pod-autoscaler-operator/internal/util"
 )
@@ -15,14 +17,26 @@ type AdmissionPluginArg string
 // These constants represent the vertical-pod-autoscaler arguments used by the
 // operator when processing VerticalPodAutoscalerController resources.
 const (
-	KubeAPIQPSArg    AdmissionPluginArg = "--kube-api-qps"
-	KubeAPIBurstArg  AdmissionPluginArg = "--kube-api-burst"
-	TLSCertFileArg   AdmissionPluginArg = "--tls-cert-file"
-	TLSKeyFileArg    AdmissionPluginArg = "--tls-private-key"
-	TLSCACertFileArg AdmissionPluginArg = "--client-ca-file"
-	WebhookTimeout   AdmissionPluginArg = "--webhook-timeout-seconds"
-	MinTLSVersionArg AdmissionPluginArg = "--min-tls-version"
-	TLSCiphersArg    AdmissionPluginArg = "--tls-ciphers"
+	KubeAPIQPSArg                 AdmissionPluginArg = "--kube-api-qps"
+	KubeAPIBurstArg               AdmissionPluginArg = "--kube-api-burst"
+	TLSCertFileArg                AdmissionPluginArg = "--tls-cert-file"
+	TLSKeyFileArg                 AdmissionPluginArg = "--tls-private-key"
+	TLSCACertFileArg              AdmissionPluginArg = "--client-ca-file"
+	WebhookTimeout                AdmissionPluginArg = "--webhook-timeout-seconds"
+	WebhookFailurePolicyFailArg   AdmissionPluginArg = "--webhook-failure-policy-fail"
+	IgnoredVPAObjectNamespacesArg AdmissionPluginArg = "--ignored-vpa-object-namespaces"
+	MinTLSVersionArg              AdmissionPluginArg = "--min-tls-version"
+	TLSCiphersArg                 AdmissionPluginArg = "--tls-ciphers"
+)
+
+// Defaults for the admission controller settings of the typed admission config.
+const (
+	// DefaultWebhookTimeoutSeconds is how long the API server waits for the webhook
+	DefaultWebhookTimeoutSeconds = int32(10)
+	// DefaultKubeAPIQPS is the rate of queries the admission controller may send to the API server
+	DefaultKubeAPIQPS = float64(25)
+	// DefaultKubeAPIBurst is the burst of queries the admission controller may send to the API server
+	DefaultKubeAPIBurst = int32(50)
 )
 
 // String returns the argument as a plain string.
@@ -39,21 +53,38 @@ func (a AdmissionPluginArg) Value(v interface{}) string {
 // to the recommnder corresponding to the values in the given
 // VerticalPodAutoscalerController resource.
 func AdmissionPluginArgs(vpa *v1.VerticalPodAutoscalerController, cfg *Config) []string {
-	s := &vpa.Spec
+	c := vpa.Spec.Admission
+	if c == nil {
+		c = &v1.AdmissionConfig{}
+	}
 
-	args := []string{
-		LogToStderrArg.String(),
-		VerbosityArg.Value(cfg.Verbosity),
+	timeout := DefaultWebhookTimeoutSeconds
+	if c.WebhookTimeoutSeconds != nil {
+		timeout = *c.WebhookTimeoutSeconds
+	}
+	qps := DefaultKubeAPIQPS
+	if c.KubeAPIQPS != nil {
+		qps = *c.KubeAPIQPS
+	}
+	burst := DefaultKubeAPIBurst
+	if c.KubeAPIBurst != nil {
+		burst = *c.KubeAPIBurst
+	}
+
+	args := loggingArgs(c.Logging, cfg)
+	args = append(args,
 		TLSCertFileArg.Value("/data/tls-certs/tls.crt"),
 		TLSKeyFileArg.Value("/data/tls-certs/tls.key"),
 		TLSCACertFileArg.Value("/data/tls-ca-certs/service-ca.crt"),
-		WebhookTimeout.Value("10"),
-	}
-	if !util.ArgExists(s.DeploymentOverrides.Admission.Container.Args, KubeAPIQPSArg.String()) {
-		args = append(args, KubeAPIQPSArg.Value("25.0"))
+		WebhookTimeout.Value(timeout),
+		KubeAPIQPSArg.Value(qps),
+		KubeAPIBurstArg.Value(burst),
+	)
+	if c.FailurePolicy != nil && *c.FailurePolicy == admissionregistrationv1.Fail {
+		args = append(args, WebhookFailurePolicyFailArg.Value(true))
 	}
-	if !util.ArgExists(s.DeploymentOverrides.Admission.Container.Args, KubeAPIBurstArg.String()) {
-		args = append(args, KubeAPIBurstArg.Value("50.0"))
+	if ignored := admissionIgnoredNamespaces(vpa, cfg); len(ignored) > 0 {
+		args = append(args, IgnoredVPAObjectNamespacesArg.Value(strings.Join(ignored, ",")))
 	}
 	if cfg.TLSProfileSpec != nil && cfg.TLSProfileSpec.MinTLSVersion != "" {
 		args = append(args, MinTLSVersionArg.Value(util.TLSVersionToArg(cfg.TLSProfileSpec.MinTLSVersion)))
@@ -61,6 +92,7 @@ func AdmissionPluginArgs(vpa *v1.VerticalPodAutoscalerController, cfg *Config) [
 	if cfg.TLSProfileSpec != nil && len(cfg.TLSProfileSpec.Ciphers) > 0 {
 		args = append(args, TLSCiphersArg.Value(strings.Join(util.TLSCiphersToArgs(cfg.TLSProfileSpec.Ciphers), ",")))
 	}
+	args = append(args, featureGatesArgs(c.FeatureGates)...)
 
 	return args
 }
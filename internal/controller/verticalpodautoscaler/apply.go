@@ -0,0 +1,215 @@
+package verticalpodautoscaler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+
+	autoscalingv1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
+)
+
+// FieldManager is the field manager the operator server-side applies the
+// objects it manages with.  It owns exactly the fields the operator renders,
+// so fields set by others, e.g. the CA bundle injected by the service CA
+// operator, are left alone.
+const FieldManager = "vertical-pod-autoscaler-operator"
+
+// ApplyResult is the outcome of applying an object.
+type ApplyResult struct {
+	// Operation is whether the object was created, updated or left unchanged.
+	Operation controllerutil.OperationResult
+	// RevertedManagers are the field managers whose changes to the fields
+	// owned by the operator were reverted, in the order they appear in the
+	// object's managed fields.
+	RevertedManagers []string
+}
+
+// applyObject server-side applies the given expected object, forcing the
+// ownership of the fields it sets.  The object is controlled by the given
+// VerticalPodAutoscalerController and labelled for pruning, unless that is nil.  Since the object is
+// rendered as a typed object, any zero value that is not omitted from its
+// JSON is applied too, so it must set every field of the object the operator
+// manages.
+func (r *VerticalPodAutoscalerControllerReconciler) applyObject(ctx context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController, obj client.Object) (ApplyResult, error) {
+	if vpa != nil {
+		if err := controllerutil.SetControllerReference(vpa, obj, r.Scheme); err != nil {
+			return ApplyResult{}, err
+		}
+		setManagedMetadata(vpa, obj)
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+
+	existing, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return ApplyResult{}, fmt.Errorf("unexpected object %T", obj)
+	}
+	err = r.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return ApplyResult{}, err
+	}
+	found := err == nil
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+	applied := &unstructured.Unstructured{Object: pruneNulls(content)}
+	applied.SetGroupVersionKind(gvk)
+	unstructured.RemoveNestedField(applied.Object, "status")
+
+	if err := r.Apply(ctx, client.ApplyConfigurationFromUnstructured(applied), client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return ApplyResult{}, err
+	}
+
+	if !found {
+		return ApplyResult{Operation: controllerutil.OperationResultCreated}, nil
+	}
+	result, err := r.Scheme.New(gvk)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(applied.Object, result); err != nil {
+		return ApplyResult{}, err
+	}
+	if !objectChanged(existing, result) {
+		return ApplyResult{Operation: controllerutil.OperationResultNone}, nil
+	}
+
+	reverted, err := revertedManagers(existing.GetManagedFields(), applied.GetManagedFields())
+	if err != nil {
+		// The object was applied all the same, only the managers are unknown.
+		klog.Errorf("Error comparing the managed fields of %s %s: %v", gvk.Kind, obj.GetName(), err)
+	}
+	return ApplyResult{Operation: controllerutil.OperationResultUpdated, RevertedManagers: reverted}, nil
+}
+
+// objectChanged returns whether the given objects differ, besides their
+// type meta, resourceVersion and managed fields.
+func objectChanged(before, after runtime.Object) bool {
+	before, after = before.DeepCopyObject(), after.DeepCopyObject()
+	for _, obj := range []runtime.Object{before, after} {
+		obj.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+		if accessor, ok := obj.(metav1.Object); ok {
+			accessor.SetResourceVersion("")
+			accessor.SetManagedFields(nil)
+		}
+	}
+	return !equality.Semantic.DeepEqual(before, after)
+}
+
+// revertedManagers returns the field managers that owned fields before an
+// apply by the operator that the operator owns after it, i.e. that changed
+// fields rendered by the operator.  Applying the values they set as well
+// would have shared the fields instead.
+func revertedManagers(before, after []metav1.ManagedFieldsEntry) ([]string, error) {
+	owned := &fieldpath.Set{}
+	afterSets := map[string]*fieldpath.Set{}
+	for _, entry := range after {
+		set, err := managedFieldSet(entry)
+		if err != nil {
+			return nil, err
+		}
+		afterSets[managedFieldsKey(entry)] = set
+		if entry.Manager == FieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			owned = owned.Union(set)
+		}
+	}
+
+	var reverted []string
+	for _, entry := range before {
+		// Fields written by earlier versions of the operator are not drift.
+		if entry.Manager == FieldManager {
+			continue
+		}
+		set, err := managedFieldSet(entry)
+		if err != nil {
+			return nil, err
+		}
+		lost := set
+		if afterSet, ok := afterSets[managedFieldsKey(entry)]; ok {
+			lost = set.Difference(afterSet)
+		}
+		if !lost.Intersection(owned).Empty() {
+			reverted = append(reverted, entry.Manager)
+		}
+	}
+	return reverted, nil
+}
+
+// managedFieldsKey identifies the given managed fields entry among those of
+// an object.
+func managedFieldsKey(entry metav1.ManagedFieldsEntry) string {
+	return strings.Join([]string{entry.Manager, string(entry.Operation), entry.Subresource}, "/")
+}
+
+// managedFieldSet returns the fields owned by the given managed fields entry.
+func managedFieldSet(entry metav1.ManagedFieldsEntry) (*fieldpath.Set, error) {
+	set := &fieldpath.Set{}
+	if entry.FieldsV1 == nil {
+		return set, nil
+	}
+	if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// pruneNulls removes the null values of the given object, such as the unset
+// creationTimestamp of a rendered object, which are not meant to be applied.
+func pruneNulls(obj map[string]interface{}) map[string]interface{} {
+	for k, v := range obj {
+		switch v := v.(type) {
+		case nil:
+			delete(obj, k)
+		case map[string]interface{}:
+			pruneNulls(v)
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					pruneNulls(m)
+				}
+			}
+		}
+	}
+	return obj
+}
+
+// recordApply records the outcome of applying the given kind of object in an
+// event, unless it was left unchanged.
+func (r *VerticalPodAutoscalerControllerReconciler) recordApply(vpaRef *corev1.ObjectReference, kind, name string, result ApplyResult) {
+	eventType, reason, action := corev1.EventTypeNormal, "SuccessfulUpdate", "Update"
+	var msg string
+	switch {
+	case result.Operation == controllerutil.OperationResultCreated:
+		reason, action = "SuccessfulCreate", "Create"
+		msg = fmt.Sprintf("Created VerticalPodAutoscalerController %s: %s", kind, name)
+	case len(result.RevertedManagers) > 0:
+		eventType, reason = corev1.EventTypeWarning, "DriftReverted"
+		msg = fmt.Sprintf("Reverted changes by %s to VerticalPodAutoscalerController %s: %s", strings.Join(result.RevertedManagers, ", "), kind, name)
+	case result.Operation == controllerutil.OperationResultUpdated:
+		msg = fmt.Sprintf("Updated VerticalPodAutoscalerController %s: %s", kind, name)
+	default:
+		return
+	}
+
+	r.Recorder.Eventf(vpaRef, nil, eventType, reason, action, "%s", msg)
+	klog.Info(msg)
+}
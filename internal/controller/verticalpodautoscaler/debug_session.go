@@ -0,0 +1,148 @@
+package verticalpodautoscaler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+
+	autoscalingv1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
+	"github.com/openshift/vertical-pod-autoscaler-operator/internal/util"
+)
+
+// Annotations of a VerticalPodAutoscalerController controlling a debug
+// session, which raises the log verbosity of an operand for a limited time.
+const (
+	// DebugSessionAnnotation starts a debug session, e.g.
+	// "component=updater,verbosity=6,ttl=30m".  The component is the command
+	// of the operand, and the session applies to every deployment running it.
+	DebugSessionAnnotation = "autoscaling.openshift.io/debug-session"
+	// DebugSessionStartedAnnotation is set by the operator to the time it
+	// started the debug session at, in RFC 3339 format.
+	DebugSessionStartedAnnotation = "autoscaling.openshift.io/debug-session-started"
+)
+
+// MaxDebugSessionTTL is the longest a debug session may last.
+const MaxDebugSessionTTL = 24 * time.Hour
+
+// DebugSession is a temporary raise of the log verbosity of an operand.
+type DebugSession struct {
+	// Command is the command of the operand.
+	Command string
+	// Verbosity is the log verbosity of the operand during the session.
+	Verbosity int
+	// TTL is how long the session lasts after it started.
+	TTL time.Duration
+}
+
+// ParseDebugSession parses the value of a DebugSessionAnnotation, a comma
+// separated list of the component, verbosity and ttl of the session.
+func ParseDebugSession(value string) (*DebugSession, error) {
+	pairs := util.ParseMapValue(value)
+	for k := range pairs {
+		if !slices.Contains([]string{"component", "verbosity", "ttl"}, k) {
+			return nil, fmt.Errorf("unknown key %q, expected component, verbosity and ttl", k)
+		}
+	}
+
+	session := &DebugSession{Command: pairs["component"]}
+	if !slices.Contains(OperandCommands, session.Command) {
+		return nil, fmt.Errorf("component must be one of %s", strings.Join(OperandCommands, ", "))
+	}
+
+	verbosity, err := strconv.Atoi(pairs["verbosity"])
+	if err != nil || verbosity < 0 || verbosity > 10 {
+		return nil, fmt.Errorf("verbosity must be between 0 and 10")
+	}
+	session.Verbosity = verbosity
+
+	ttl, err := time.ParseDuration(pairs["ttl"])
+	if err != nil || ttl <= 0 || ttl > MaxDebugSessionTTL {
+		return nil, fmt.Errorf("ttl must be a duration of at most %s, e.g. 30m", MaxDebugSessionTTL)
+	}
+	session.TTL = ttl
+
+	return session, nil
+}
+
+// debugSession returns the debug session of the given
+// VerticalPodAutoscalerController, or nil if it has none or it is invalid.
+func debugSession(vpa *autoscalingv1.VerticalPodAutoscalerController) *DebugSession {
+	value, ok := vpa.Annotations[DebugSessionAnnotation]
+	if !ok {
+		return nil
+	}
+	session, err := ParseDebugSession(value)
+	if err != nil {
+		return nil
+	}
+	return session
+}
+
+// debugSessionStart returns the time the debug session of the given
+// VerticalPodAutoscalerController was started at, if it was.
+func debugSessionStart(vpa *autoscalingv1.VerticalPodAutoscalerController) (time.Time, bool) {
+	started, err := time.Parse(time.RFC3339, vpa.Annotations[DebugSessionStartedAnnotation])
+	return started, err == nil
+}
+
+// syncDebugSession starts the debug session of the given
+// VerticalPodAutoscalerController, or ends it once it expired by removing its
+// annotations.  It returns how long the session still lasts, or zero if there
+// is no active session.  An invalid session is ignored, and reported in the
+// status.
+func (r *VerticalPodAutoscalerControllerReconciler) syncDebugSession(ctx context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController, vpaRef *corev1.ObjectReference) (time.Duration, error) {
+	_, hasSession := vpa.Annotations[DebugSessionAnnotation]
+	_, hasStart := vpa.Annotations[DebugSessionStartedAnnotation]
+	if !hasSession {
+		// The session was ended by hand.
+		if hasStart {
+			delete(vpa.Annotations, DebugSessionStartedAnnotation)
+			if err := r.Update(ctx, vpa); err != nil {
+				return 0, err
+			}
+		}
+		return 0, nil
+	}
+
+	session := debugSession(vpa)
+	if session == nil {
+		return 0, nil
+	}
+
+	// A start time in the future cannot have been set by the operator, and
+	// would make the session outlast its ttl, so the session starts over.
+	now := time.Now()
+	started, ok := debugSessionStart(vpa)
+	if !ok || started.After(now) {
+		vpa.Annotations[DebugSessionStartedAnnotation] = now.UTC().Format(time.RFC3339)
+		if err := r.Update(ctx, vpa); err != nil {
+			return 0, err
+		}
+
+		msg := fmt.Sprintf("Started debug session raising the verbosity of the %s to %d for %s", session.Command, session.Verbosity, session.TTL)
+		r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeNormal, "DebugSessionStarted", "StartDebugSession", "%s", msg)
+		klog.Info(msg)
+		return session.TTL, nil
+	}
+
+	if remaining := started.Add(session.TTL).Sub(now); remaining > 0 {
+		return remaining, nil
+	}
+
+	delete(vpa.Annotations, DebugSessionAnnotation)
+	delete(vpa.Annotations, DebugSessionStartedAnnotation)
+	if err := r.Update(ctx, vpa); err != nil {
+		return 0, err
+	}
+
+	msg := fmt.Sprintf("Debug session of the %s expired, reverting its verbosity", session.Command)
+	r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeNormal, "DebugSessionExpired", "EndDebugSession", "%s", msg)
+	klog.Info(msg)
+	return 0, nil
+}
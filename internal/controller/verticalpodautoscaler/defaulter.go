@@ -0,0 +1,47 @@
+package verticalpodautoscaler
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	autoscalingv1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
+)
+
+// Defaulter sets default values on VerticalPodAutoscalerController resources.
+type Defaulter struct{}
+
+var _ admission.Defaulter[*autoscalingv1.VerticalPodAutoscalerController] = &Defaulter{}
+
+// Default sets the defaults on the given VerticalPodAutoscalerController.
+func (d *Defaulter) Default(_ context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController) error {
+	SetDefaults(vpa)
+	return nil
+}
+
+// SetDefaults fills in every unset field of the given
+// VerticalPodAutoscalerController with the value the operator defaults it to.
+func SetDefaults(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+	s := &vpa.Spec
+
+	if s.SafetyMarginFraction == nil {
+		v := DefaultSafetyMarginFraction
+		s.SafetyMarginFraction = &v
+	}
+	if s.PodMinCPUMillicores == nil {
+		v := DefaultPodMinCPUMillicores
+		s.PodMinCPUMillicores = &v
+	}
+	if s.PodMinMemoryMb == nil {
+		v := DefaultPodMinMemoryMb
+		s.PodMinMemoryMb = &v
+	}
+	if s.RecommendationOnly == nil {
+		v := DefaultRecommendationOnly
+		s.RecommendationOnly = &v
+	}
+	if s.MinReplicas == nil {
+		v := DefaultMinReplicas
+		s.MinReplicas = &v
+	}
+}
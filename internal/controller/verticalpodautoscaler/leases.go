@@ -0,0 +1,114 @@
+package verticalpodautoscaler
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	autoscalingv1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
+	"github.com/openshift/vertical-pod-autoscaler-operator/internal/util"
+)
+
+// staticLeaseNames are the leases the leader-locking Roles shipped with the
+// operator grant access to, those of the default recommender and the updater.
+var staticLeaseNames = sets.New(RecommenderLeaseName, UpdaterLeaseName)
+
+// leaderElectionLeases returns the names of the leases the enabled operands of
+// the given VerticalPodAutoscalerController elect a leader through, by service
+// account, leaving out those the shipped Roles already grant access to.
+func (r *VerticalPodAutoscalerControllerReconciler) leaderElectionLeases(vpa *autoscalingv1.VerticalPodAutoscalerController) map[string]sets.Set[string] {
+	leases := map[string]sets.Set[string]{}
+	for _, params := range OperandParams(vpa) {
+		if params.LeaseName == "" || staticLeaseNames.Has(params.LeaseName) || !params.EnabledMethod(r, vpa) {
+			continue
+		}
+		if leases[params.ServiceAccount] == nil {
+			leases[params.ServiceAccount] = sets.New[string]()
+		}
+		leases[params.ServiceAccount].Insert(params.LeaseName)
+	}
+	return leases
+}
+
+// leaderLockingName returns the name of the Role and RoleBinding granting the
+// given service account access to the leases of the given
+// VerticalPodAutoscalerController.
+func leaderLockingName(vpa *autoscalingv1.VerticalPodAutoscalerController, serviceAccount string) string {
+	return fmt.Sprintf("system:leader-locking-%s-%s", serviceAccount, vpa.Name)
+}
+
+// LeaderLockingRoles returns the expected Roles granting the operands of the
+// given VerticalPodAutoscalerController access to the leases they elect a
+// leader through, one per service account.  The names of the leases of the
+// alternative recommenders depend on the resource, so unlike those of the
+// default recommender and updater they cannot be listed in the Roles shipped
+// with the operator.  Creating leases is granted by those Roles, since it
+// cannot be restricted by name.
+func (r *VerticalPodAutoscalerControllerReconciler) LeaderLockingRoles(vpa *autoscalingv1.VerticalPodAutoscalerController) []rbacv1.Role {
+	leases := r.leaderElectionLeases(vpa)
+	var roles []rbacv1.Role
+	for _, serviceAccount := range slices.Sorted(maps.Keys(leases)) {
+		roles = append(roles, rbacv1.Role{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: rbacv1.SchemeGroupVersion.String(),
+				Kind:       "Role",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      leaderLockingName(vpa, serviceAccount),
+				Namespace: r.Config.Namespace,
+				Annotations: map[string]string{
+					util.ReleaseVersionAnnotation: r.Config.ReleaseVersion,
+				},
+			},
+			Rules: []rbacv1.PolicyRule{
+				{
+					APIGroups:     []string{"coordination.k8s.io"},
+					Resources:     []string{"leases"},
+					ResourceNames: sets.List(leases[serviceAccount]),
+					Verbs:         []string{"get", "watch", "update"},
+				},
+			},
+		})
+	}
+	return roles
+}
+
+// LeaderLockingRoleBindings returns the expected RoleBindings of the
+// LeaderLockingRoles of the given VerticalPodAutoscalerController.
+func (r *VerticalPodAutoscalerControllerReconciler) LeaderLockingRoleBindings(vpa *autoscalingv1.VerticalPodAutoscalerController) []rbacv1.RoleBinding {
+	leases := r.leaderElectionLeases(vpa)
+	var bindings []rbacv1.RoleBinding
+	for _, serviceAccount := range slices.Sorted(maps.Keys(leases)) {
+		name := leaderLockingName(vpa, serviceAccount)
+		bindings = append(bindings, rbacv1.RoleBinding{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: rbacv1.SchemeGroupVersion.String(),
+				Kind:       "RoleBinding",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: r.Config.Namespace,
+				Annotations: map[string]string{
+					util.ReleaseVersionAnnotation: r.Config.ReleaseVersion,
+				},
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "Role",
+				Name:     name,
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      serviceAccount,
+					Namespace: r.Config.Namespace,
+				},
+			},
+		})
+	}
+	return bindings
+}
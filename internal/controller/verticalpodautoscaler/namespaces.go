@@ -0,0 +1,131 @@
+package verticalpodautoscaler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	v1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
+)
+
+// PlatformNamespaces are the namespaces of the platform that the operands
+// never act on, whether or not they exist yet.
+var PlatformNamespaces = []string{"default", "kube-node-lease", "kube-public", "kube-system", "openshift"}
+
+// PlatformNamespacePrefixes are the prefixes of the names of the other
+// namespaces of the platform, which the operands never act on either.
+var PlatformNamespacePrefixes = []string{"kube-", "openshift-"}
+
+// WebhookPlatformNamespacesCondition is the name of the match condition that
+// keeps the webhook registered by the admission controller away from the
+// platform namespaces by prefix, including those created since the operator
+// last updated its namespace selector.
+const WebhookPlatformNamespacesCondition = "exclude-platform-namespaces"
+
+// IsPlatformNamespace returns whether the namespace with the given name
+// belongs to the platform.
+func IsPlatformNamespace(name string) bool {
+	return slices.Contains(PlatformNamespaces, name) || hasPlatformPrefix(name)
+}
+
+// hasPlatformPrefix returns whether the given namespace name has one of the
+// PlatformNamespacePrefixes.  Only the namespaces with such a prefix change
+// the ignored namespaces as they come and go.
+func hasPlatformPrefix(name string) bool {
+	return slices.ContainsFunc(PlatformNamespacePrefixes, func(prefix string) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+}
+
+// IgnoredNamespaces returns the namespaces the operands of the given
+// VerticalPodAutoscalerController do not act on, in sorted order: the platform
+// namespaces, including the existing ones with a platform prefix, the
+// operator's namespace and its ignoredNamespaces.  The recommender and updater
+// only ignore namespaces by name, so they are rolled out again as platform
+// namespaces come and go, but not as any other namespace does.
+func IgnoredNamespaces(vpa *v1.VerticalPodAutoscalerController, cfg *Config) []string {
+	ignored := sets.New(PlatformNamespaces...)
+	for _, ns := range cfg.Namespaces {
+		if hasPlatformPrefix(ns) {
+			ignored.Insert(ns)
+		}
+	}
+	ignored.Insert(cfg.Namespace)
+	ignored.Insert(vpa.Spec.IgnoredNamespaces...)
+	return sets.List(ignored)
+}
+
+// CoveredNamespaceCount returns the number of existing namespaces the
+// recommenders and updater of the given VerticalPodAutoscalerController act
+// on.
+func CoveredNamespaceCount(vpa *v1.VerticalPodAutoscalerController, cfg *Config) int32 {
+	ignored := sets.New(IgnoredNamespaces(vpa, cfg)...)
+	var covered int32
+	for _, ns := range cfg.Namespaces {
+		if !ignored.Has(ns) {
+			covered++
+		}
+	}
+	return covered
+}
+
+// WebhookNamespaceSelector returns the namespace selector of the webhook
+// registered by the admission controller of the given
+// VerticalPodAutoscalerController: its namespaceSelector as is, further
+// excluding the namespaces the admission controller ignores by name.
+func WebhookNamespaceSelector(vpa *v1.VerticalPodAutoscalerController, cfg *Config) *metav1.LabelSelector {
+	selector := &metav1.LabelSelector{}
+	if vpa.Spec.NamespaceSelector != nil {
+		selector = vpa.Spec.NamespaceSelector.DeepCopy()
+	}
+	selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      corev1.LabelMetadataName,
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   admissionIgnoredNamespaces(vpa, cfg),
+	})
+	return selector
+}
+
+// webhookPlatformNamespacesExpression returns the CEL expression of the
+// WebhookPlatformNamespacesCondition, which only matches requests outside of
+// the namespaces with a platform prefix.
+func webhookPlatformNamespacesExpression() string {
+	var conditions []string
+	for _, prefix := range PlatformNamespacePrefixes {
+		conditions = append(conditions, fmt.Sprintf("!request.namespace.startsWith(%q)", prefix))
+	}
+	return strings.Join(conditions, " && ")
+}
+
+// admissionIgnoredNamespaces returns the namespaces the admission controller
+// of the given VerticalPodAutoscalerController ignores, in sorted order.
+func admissionIgnoredNamespaces(vpa *v1.VerticalPodAutoscalerController, cfg *Config) []string {
+	ignored := sets.New(IgnoredNamespaces(vpa, cfg)...)
+	if vpa.Spec.Admission != nil {
+		ignored.Insert(vpa.Spec.Admission.IgnoredNamespaces...)
+	}
+	return sets.List(ignored)
+}
+
+// syncNamespaces updates the config with the names of every namespace of the
+// cluster, among which the platform namespaces are ignored and the covered
+// namespaces counted.  The config is left as is if they cannot be listed.
+func (r *VerticalPodAutoscalerControllerReconciler) syncNamespaces(ctx context.Context) error {
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(namespaces.Items))
+	for _, ns := range namespaces.Items {
+		names = append(names, ns.Name)
+	}
+	slices.Sort(names)
+	r.Config.Namespaces = names
+	return nil
+}
@@ -0,0 +1,183 @@
+package verticalpodautoscaler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	autoscalingv1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
+)
+
+// ManagedByLabel is set on the objects the operator manages to the name of the
+// VerticalPodAutoscalerController they belong to, so those it no longer renders
+// can be found and pruned.
+const ManagedByLabel = "autoscaling.openshift.io/managed-by"
+
+// prunedKinds are the kinds of the objects the operator prunes, along with a
+// constructor of their list type.
+var prunedKinds = []struct {
+	kind    string
+	newList func() client.ObjectList
+}{
+	{"Deployment", func() client.ObjectList { return &appsv1.DeploymentList{} }},
+	{"PodDisruptionBudget", func() client.ObjectList { return &policyv1.PodDisruptionBudgetList{} }},
+	{"NetworkPolicy", func() client.ObjectList { return &networkingv1.NetworkPolicyList{} }},
+	{"Service", func() client.ObjectList { return &corev1.ServiceList{} }},
+	{"ConfigMap", func() client.ObjectList { return &corev1.ConfigMapList{} }},
+	{"Role", func() client.ObjectList { return &rbacv1.RoleList{} }},
+	{"RoleBinding", func() client.ObjectList { return &rbacv1.RoleBindingList{} }},
+}
+
+// staleObject is an object managed by the operator that it no longer renders.
+type staleObject struct {
+	kind string
+	client.Object
+}
+
+// String returns the kind and name of the object.
+func (o staleObject) String() string {
+	return objectKey(o.kind, o.GetName())
+}
+
+// objectKey identifies an object of the given kind in the operator's namespace.
+func objectKey(kind, name string) string {
+	return fmt.Sprintf("%s: %s", kind, name)
+}
+
+// setManagedMetadata sets the label tracking the objects of the given
+// VerticalPodAutoscalerController on the given object.  The labels are copied,
+// since rendered objects may share them with their selectors.
+func setManagedMetadata(vpa *autoscalingv1.VerticalPodAutoscalerController, obj client.Object) {
+	labels := maps.Clone(obj.GetLabels())
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ManagedByLabel] = vpa.Name
+	obj.SetLabels(labels)
+}
+
+// expectedObjects returns the objects the operator currently renders for the
+// given VerticalPodAutoscalerController, as kind: name.
+func (r *VerticalPodAutoscalerControllerReconciler) expectedObjects(vpa *autoscalingv1.VerticalPodAutoscalerController) sets.Set[string] {
+	expected := sets.New[string]()
+	for _, params := range OperandParams(vpa) {
+		if !params.EnabledMethod(r, vpa) {
+			continue
+		}
+		name := params.NameMethod(r, vpa).Name
+		expected.Insert(objectKey("Deployment", name))
+		if r.PodDisruptionBudget(vpa, params) != nil {
+			expected.Insert(objectKey("PodDisruptionBudget", name))
+		}
+	}
+	for _, policy := range r.NetworkPolicies(vpa) {
+		expected.Insert(objectKey("NetworkPolicy", policy.Name))
+	}
+	if r.AdmissionPluginEnabled(vpa) {
+		expected.Insert(objectKey("Service", WebhookServiceName))
+	}
+	expected.Insert(objectKey("ConfigMap", CACertConfigMapName))
+	for _, role := range r.LeaderLockingRoles(vpa) {
+		expected.Insert(objectKey("Role", role.Name))
+	}
+	for _, binding := range r.LeaderLockingRoleBindings(vpa) {
+		expected.Insert(objectKey("RoleBinding", binding.Name))
+	}
+	return expected
+}
+
+// staleObjects returns the objects managed for the given
+// VerticalPodAutoscalerController that it no longer renders, such as those of
+// a removed alternative recommender or a disabled operand, or those left behind
+// by an older release.  Objects created before the operator labelled them are
+// recognized by their controller reference.
+func (r *VerticalPodAutoscalerControllerReconciler) staleObjects(ctx context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController) ([]staleObject, error) {
+	expected := r.expectedObjects(vpa)
+
+	var stale []staleObject
+	for _, k := range prunedKinds {
+		list := k.newList()
+		if err := r.List(ctx, list, client.InNamespace(r.Config.Namespace)); err != nil {
+			return nil, err
+		}
+		objs, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range objs {
+			obj, ok := o.(client.Object)
+			if !ok || expected.Has(objectKey(k.kind, obj.GetName())) {
+				continue
+			}
+			if obj.GetLabels()[ManagedByLabel] == vpa.Name || metav1.IsControlledBy(obj, vpa) {
+				stale = append(stale, staleObject{kind: k.kind, Object: obj})
+			}
+		}
+	}
+	return stale, nil
+}
+
+// StaleObjects returns the objects managed for the given
+// VerticalPodAutoscalerController that it no longer renders, as kind: name.
+func (r *VerticalPodAutoscalerControllerReconciler) StaleObjects(ctx context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController) ([]string, error) {
+	stale, err := r.staleObjects(ctx, vpa)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, obj := range stale {
+		names = append(names, obj.String())
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// pruneStaleObjects deletes the objects managed for the given
+// VerticalPodAutoscalerController that it no longer renders, recording a
+// failure for each one it cannot delete.  Objects of a kind that failed to
+// apply in this pass are kept, since they may still serve in place of the
+// objects that failed, e.g. a NetworkPolicy that was renamed.
+func (r *VerticalPodAutoscalerControllerReconciler) pruneStaleObjects(ctx context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController, vpaRef *corev1.ObjectReference, failures *reconcileFailures) {
+	failedKinds := sets.New[string]()
+	for _, f := range *failures {
+		failedKinds.Insert(f.Kind)
+	}
+
+	stale, err := r.staleObjects(ctx, vpa)
+	if err != nil {
+		failures.add(reconcileFailure{
+			Kind: "VerticalPodAutoscalerController", Name: vpa.Name, Reason: "FailedList",
+			Message: fmt.Sprintf("Error listing stale vertical-pod-autoscaler objects: %v", err),
+		})
+		return
+	}
+
+	for _, obj := range stale {
+		if failedKinds.Has(obj.kind) {
+			continue
+		}
+		if err := r.Delete(ctx, obj.Object); err != nil && !errors.IsNotFound(err) {
+			failures.add(reconcileFailure{
+				Kind: obj.kind, Name: obj.GetName(), Reason: "FailedDelete",
+				Message: fmt.Sprintf("Error deleting stale VerticalPodAutoscalerController %s: %v", obj, err),
+			})
+			continue
+		}
+		msg := fmt.Sprintf("Deleted VerticalPodAutoscalerController %s", obj)
+		r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeNormal, "SuccessfulDelete", "Delete", "%s", msg)
+		klog.Info(msg)
+	}
+}
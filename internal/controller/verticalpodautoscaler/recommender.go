@@ -2,8 +2,13 @@ package verticalpodautoscaler
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	v1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
+	"github.com/openshift/vertical-pod-autoscaler-operator/internal/util"
 )
 
 // RecommenderArg represents a command line argument to the VPA's recommender
@@ -25,21 +30,176 @@ func (a RecommenderArg) Value(v interface{}) string {
 const (
 	LogToStderrArg          RecommenderArg = "--logtostderr"
 	VerbosityArg            RecommenderArg = "--v"
+	VModuleArg              RecommenderArg = "--vmodule"
+	LoggingFormatArg        RecommenderArg = "--logging-format"
+	FeatureGatesArg         RecommenderArg = "--feature-gates"
 	SafetyMarginFractionArg RecommenderArg = "--recommendation-margin-fraction"
 	PodMinCPUMillicoresArg  RecommenderArg = "--pod-recommendation-min-cpu-millicores"
 	PodMinMemoryMbArg       RecommenderArg = "--pod-recommendation-min-memory-mb"
+
+	CPUHistogramDecayHalfLifeArg    RecommenderArg = "--cpu-histogram-decay-half-life"
+	MemoryHistogramDecayHalfLifeArg RecommenderArg = "--memory-histogram-decay-half-life"
+	TargetCPUPercentileArg          RecommenderArg = "--target-cpu-percentile"
+	TargetMemoryPercentileArg       RecommenderArg = "--target-memory-percentile"
+	OOMBumpUpRatioArg               RecommenderArg = "--oom-bump-up-ratio"
+	OOMMinBumpUpBytesArg            RecommenderArg = "--oom-min-bump-up-bytes"
+	RecommenderIntervalArg          RecommenderArg = "--recommender-interval"
+	CheckpointsGCIntervalArg        RecommenderArg = "--checkpoints-gc-interval"
+	MemorySaverArg                  RecommenderArg = "--memory-saver"
+
+	StorageArg                   RecommenderArg = "--storage"
+	PrometheusAddressArg         RecommenderArg = "--prometheus-address"
+	PrometheusBearerTokenFileArg RecommenderArg = "--prometheus-bearer-token-file"
+	PrometheusCadvisorJobNameArg RecommenderArg = "--prometheus-cadvisor-job-name"
+	PodNamespaceLabelArg         RecommenderArg = "--pod-namespace-label"
+	PodNameLabelArg              RecommenderArg = "--pod-name-label"
+	PodLabelPrefixArg            RecommenderArg = "--pod-label-prefix"
+	MetricForPodLabelsArg        RecommenderArg = "--metric-for-pod-labels"
+	ContainerNamespaceLabelArg   RecommenderArg = "--container-namespace-label"
+	ContainerPodNameLabelArg     RecommenderArg = "--container-pod-name-label"
+	ContainerNameLabelArg        RecommenderArg = "--container-name-label"
+
+	RecommenderNameArg RecommenderArg = "--recommender-name"
+
+	LeaderElectArg                  RecommenderArg = "--leader-elect"
+	LeaderElectResourceNameArg      RecommenderArg = "--leader-elect-resource-name"
+	LeaderElectResourceNamespaceArg RecommenderArg = "--leader-elect-resource-namespace"
 )
 
+// Names of the leases used for leader election by the recommender and updater,
+// which match the upstream defaults.
+const (
+	RecommenderLeaseName = "vpa-recommender-lease"
+	UpdaterLeaseName     = "vpa-updater"
+)
+
+// DefaultRecommenderName is the name VerticalPodAutoscalers select the
+// default recommender by.
+const DefaultRecommenderName = "default"
+
+// Defaults of the Prometheus history provider of the recommender, which match
+// the OpenShift cluster monitoring stack.  The labels of pods are read from
+// the kube_pod_labels metric of kube-state-metrics.
+const (
+	DefaultPrometheusAddress        = "https://thanos-querier.openshift-monitoring.svc:9091"
+	DefaultPrometheusJobName        = "kubelet"
+	DefaultPrometheusNamespaceLabel = "namespace"
+	DefaultPrometheusPodLabel       = "pod"
+	DefaultPrometheusContainerLabel = "container"
+	PrometheusPodLabelPrefix        = "label_"
+	PrometheusMetricForPodLabels    = `kube_pod_labels{job="kube-state-metrics"}`
+)
+
+// Paths of the service account token and service CA bundle mounted into
+// recommenders that load their history from Prometheus.  The bundle is alone
+// in its directory, which is added to the system certificate directories.
+const (
+	PrometheusCredentialsPath = "/var/run/secrets/prometheus"
+	PrometheusTokenFile       = PrometheusCredentialsPath + "/token"
+	PrometheusCADir           = PrometheusCredentialsPath + "/service-ca"
+)
+
+// SystemCertDirs are the directories Go loads trusted certificates from by
+// default, which the SSL_CERT_DIR environment variable replaces.
+var SystemCertDirs = []string{"/etc/ssl/certs", "/etc/pki/tls/certs"}
+
+// KnownFeatureGates are the feature gates of the VPA version shipped with the
+// operator.  All of its operands share the same gates.
+var KnownFeatureGates = sets.New("InPlaceOrRecreate")
+
+// NamedRecommenderLeaseName returns the name of the lease used for leader
+// election by the alternative recommender with the given name.
+func NamedRecommenderLeaseName(name string) string {
+	return fmt.Sprintf("vpa-recommender-%s-lease", name)
+}
+
+// leaderElectionArgs returns the arguments enabling leader election on the
+// given lease, if the operand runs with more than one replica.
+func leaderElectionArgs(replicas *int32, leaseName string, cfg *Config) []string {
+	if replicas == nil || *replicas <= 1 {
+		return nil
+	}
+	return []string{
+		LeaderElectArg.Value(true),
+		LeaderElectResourceNameArg.Value(leaseName),
+		LeaderElectResourceNamespaceArg.Value(cfg.Namespace),
+	}
+}
+
+// loggingArgs returns the arguments configuring the logs of an operand with
+// the given logging config, with the verbosity defaulting to the operator's.
+// Only text logs are explicitly sent to stderr, since the JSON format rejects
+// the klog output flags.
+func loggingArgs(l *v1.LoggingConfig, cfg *Config) []string {
+	if l == nil {
+		l = &v1.LoggingConfig{}
+	}
+
+	var args []string
+	if l.Format == v1.LogFormatJSON {
+		args = append(args, LoggingFormatArg.Value("json"))
+	} else {
+		args = append(args, LogToStderrArg.String())
+	}
+	if l.Verbosity != nil {
+		args = append(args, VerbosityArg.Value(*l.Verbosity))
+	} else {
+		args = append(args, VerbosityArg.Value(cfg.Verbosity))
+	}
+	if l.VModule != "" {
+		args = append(args, VModuleArg.Value(l.VModule))
+	}
+	return args
+}
+
+// featureGatesArgs returns the argument setting the given feature gates, if
+// any, as a single flag with the gates sorted by name.
+func featureGatesArgs(gates map[string]bool) []string {
+	if len(gates) == 0 {
+		return nil
+	}
+	pairs := map[string]string{}
+	for gate, enabled := range gates {
+		pairs[gate] = strconv.FormatBool(enabled)
+	}
+	return []string{FeatureGatesArg.Value(util.FormatMapValue(pairs))}
+}
+
+// ignoredNamespacesArgs returns the arguments making the recommender or
+// updater of the given VerticalPodAutoscalerController ignore the namespaces
+// it does not act on.
+func ignoredNamespacesArgs(vpa *v1.VerticalPodAutoscalerController, cfg *Config) []string {
+	return []string{IgnoredVPAObjectNamespacesArg.Value(strings.Join(IgnoredNamespaces(vpa, cfg), ","))}
+}
+
 // RecommenderArgs returns a slice of strings representing command line arguments
 // to the recommnder corresponding to the values in the given
 // VerticalPodAutoscalerController resource.
 func RecommenderArgs(vpa *v1.VerticalPodAutoscalerController, cfg *Config) []string {
 	s := &vpa.Spec
+	return recommenderArgs(vpa, s.Recommender, s.DeploymentOverrides.Recommender.Replicas, RecommenderLeaseName, cfg)
+}
 
-	args := []string{
-		LogToStderrArg.String(),
-		VerbosityArg.Value(cfg.Verbosity),
+// NamedRecommenderArgs returns a slice of strings representing command line
+// arguments to the given alternative recommender of the given
+// VerticalPodAutoscalerController resource.
+func NamedRecommenderArgs(vpa *v1.VerticalPodAutoscalerController, rec *v1.NamedRecommender, cfg *Config) []string {
+	args := recommenderArgs(vpa, rec.Config, rec.DeploymentOverride.Replicas, NamedRecommenderLeaseName(rec.Name), cfg)
+	return append(args, RecommenderNameArg.Value(rec.Name))
+}
+
+// recommenderArgs returns the arguments shared by all recommenders, tuned
+// with the given config.
+func recommenderArgs(vpa *v1.VerticalPodAutoscalerController, c *v1.RecommenderConfig, replicas *int32, leaseName string, cfg *Config) []string {
+	s := &vpa.Spec
+
+	var logging *v1.LoggingConfig
+	if c != nil {
+		logging = c.Logging
 	}
+	args := loggingArgs(logging, cfg)
+	args = append(args, leaderElectionArgs(replicas, leaseName, cfg)...)
+	args = append(args, ignoredNamespacesArgs(vpa, cfg)...)
 	if s.SafetyMarginFraction != nil {
 		v := SafetyMarginFractionArg.Value(*s.SafetyMarginFraction)
 		args = append(args, v)
@@ -53,5 +213,66 @@ func RecommenderArgs(vpa *v1.VerticalPodAutoscalerController, cfg *Config) []str
 		args = append(args, v)
 	}
 
+	if c != nil {
+		if c.CPUHistogramDecayHalfLife != nil {
+			args = append(args, CPUHistogramDecayHalfLifeArg.Value(c.CPUHistogramDecayHalfLife.Duration))
+		}
+		if c.MemoryHistogramDecayHalfLife != nil {
+			args = append(args, MemoryHistogramDecayHalfLifeArg.Value(c.MemoryHistogramDecayHalfLife.Duration))
+		}
+		if c.TargetCPUPercentile != nil {
+			args = append(args, TargetCPUPercentileArg.Value(*c.TargetCPUPercentile))
+		}
+		if c.TargetMemoryPercentile != nil {
+			args = append(args, TargetMemoryPercentileArg.Value(*c.TargetMemoryPercentile))
+		}
+		if c.OOMBumpUpRatio != nil {
+			args = append(args, OOMBumpUpRatioArg.Value(*c.OOMBumpUpRatio))
+		}
+		if c.OOMMinBumpUp != nil {
+			args = append(args, OOMMinBumpUpBytesArg.Value(c.OOMMinBumpUp.Value()))
+		}
+		if c.RecommenderInterval != nil {
+			args = append(args, RecommenderIntervalArg.Value(c.RecommenderInterval.Duration))
+		}
+		if c.CheckpointsGCInterval != nil {
+			args = append(args, CheckpointsGCIntervalArg.Value(c.CheckpointsGCInterval.Duration))
+		}
+		if c.MemorySaver != nil {
+			args = append(args, MemorySaverArg.Value(*c.MemorySaver))
+		}
+		if c.History != nil {
+			args = append(args, historyArgs(c.History)...)
+		}
+		args = append(args, featureGatesArgs(c.FeatureGates)...)
+	}
+
 	return args
 }
+
+// historyArgs returns the arguments making the recommender load its history
+// from Prometheus, with the defaults of the given history config filled in.
+func historyArgs(h *v1.RecommenderHistory) []string {
+	withDefault := func(v, def string) string {
+		if v == "" {
+			return def
+		}
+		return v
+	}
+	namespaceLabel := withDefault(h.NamespaceLabel, DefaultPrometheusNamespaceLabel)
+	podLabel := withDefault(h.PodLabel, DefaultPrometheusPodLabel)
+
+	return []string{
+		StorageArg.Value("prometheus"),
+		PrometheusAddressArg.Value(withDefault(h.Address, DefaultPrometheusAddress)),
+		PrometheusBearerTokenFileArg.Value(PrometheusTokenFile),
+		PrometheusCadvisorJobNameArg.Value(withDefault(h.JobName, DefaultPrometheusJobName)),
+		PodNamespaceLabelArg.Value(namespaceLabel),
+		PodNameLabelArg.Value(podLabel),
+		PodLabelPrefixArg.Value(PrometheusPodLabelPrefix),
+		MetricForPodLabelsArg.Value(PrometheusMetricForPodLabels),
+		ContainerNamespaceLabelArg.Value(namespaceLabel),
+		ContainerPodNameLabelArg.Value(podLabel),
+		ContainerNameLabelArg.Value(withDefault(h.ContainerLabel, DefaultPrometheusContainerLabel)),
+	}
+}
@@ -0,0 +1,413 @@
+package verticalpodautoscaler
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog"
+
+	autoscalingv1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
+	"github.com/openshift/vertical-pod-autoscaler-operator/internal/util"
+)
+
+// Reasons used in the status conditions of a VerticalPodAutoscalerController.
+const (
+	ReasonAsExpected               = "AsExpected"
+	ReasonDisabled                 = "Disabled"
+	ReasonDeploymentNotFound       = "DeploymentNotFound"
+	ReasonDeploymentUnavailable    = "DeploymentUnavailable"
+	ReasonDeploymentUpdating       = "DeploymentUpdating"
+	ReasonDeploymentScalingDown    = "DeploymentScalingDown"
+	ReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+	ReasonReplicaFailure           = "ReplicaFailure"
+	ReasonOperandsUnavailable      = "OperandsUnavailable"
+	ReasonOperandsProgressing      = "OperandsProgressing"
+	ReasonOperandsDegraded         = "OperandsDegraded"
+	ReasonExtraArgsInvalid         = "ExtraArgsInvalid"
+	ReasonDebugSessionActive       = "DebugSessionActive"
+	ReasonDebugSessionInvalid      = "DebugSessionInvalid"
+)
+
+// reconcileFailure records why the operator failed to reconcile a resource
+// during a single pass of Reconcile.
+type reconcileFailure struct {
+	// Operand is the app name of the operand the resource belongs to, or empty
+	// for resources shared by all operands.
+	Operand string
+	// Kind and Name identify the resource.
+	Kind    string
+	Name    string
+	Reason  string
+	Message string
+}
+
+// conditionType returns the type of the condition reporting the failure.  The
+// characters of the name that a condition type does not allow, such as the
+// colon of the leader-locking Roles, are replaced by underscores.
+func (f reconcileFailure) conditionType() string {
+	name := strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' || r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, f.Name)
+	return autoscalingv1.ConditionReconcileFailedPrefix + strings.ToLower(f.Kind) + "." + name
+}
+
+// reconcileFailures collects the failures of a single reconcile pass, in the
+// order they occurred.
+type reconcileFailures []reconcileFailure
+
+// add logs the given failure and records it.
+func (f *reconcileFailures) add(failure reconcileFailure) {
+	klog.Error(failure.Message)
+	*f = append(*f, failure)
+}
+
+// operand returns the first failure recorded for the operand with the given
+// app name, or for the shared resources if it is empty.
+func (f reconcileFailures) operand(appName string) (reconcileFailure, bool) {
+	for _, failure := range f {
+		if failure.Operand == appName {
+			return failure, true
+		}
+	}
+	return reconcileFailure{}, false
+}
+
+// resources returns the kinds and names of the resources that failed.
+func (f reconcileFailures) resources() []string {
+	var resources []string
+	for _, failure := range f {
+		resources = append(resources, objectKey(failure.Kind, failure.Name))
+	}
+	return resources
+}
+
+// err returns the failures as a single error, or nil if there are none.
+func (f reconcileFailures) err() error {
+	var errs []error
+	for _, failure := range f {
+		errs = append(errs, goerrors.New(failure.Message))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// RecommenderStatus returns the recommender's part of the given status.
+func RecommenderStatus(status *autoscalingv1.VerticalPodAutoscalerControllerStatus) *autoscalingv1.OperandStatus {
+	return &status.Recommender
+}
+
+// UpdaterStatus returns the updater's part of the given status.
+func UpdaterStatus(status *autoscalingv1.VerticalPodAutoscalerControllerStatus) *autoscalingv1.OperandStatus {
+	return &status.Updater
+}
+
+// AdmissionPluginStatus returns the admission controller's part of the given status.
+func AdmissionPluginStatus(status *autoscalingv1.VerticalPodAutoscalerControllerStatus) *autoscalingv1.OperandStatus {
+	return &status.Admission
+}
+
+// NamedRecommenderStatus returns the part of the given status of the
+// alternative recommender with the given name, adding it if missing.
+func NamedRecommenderStatus(status *autoscalingv1.VerticalPodAutoscalerControllerStatus, name string) *autoscalingv1.OperandStatus {
+	for i := range status.Recommenders {
+		if status.Recommenders[i].Name == name {
+			return &status.Recommenders[i].OperandStatus
+		}
+	}
+	status.Recommenders = append(status.Recommenders, autoscalingv1.NamedOperandStatus{Name: name})
+	return &status.Recommenders[len(status.Recommenders)-1].OperandStatus
+}
+
+// SyncStatus computes the status of the given VerticalPodAutoscalerController
+// from its operand deployments and the failures recorded during the current
+// reconcile pass, and writes it to the status subresource if it changed.
+func (r *VerticalPodAutoscalerControllerReconciler) SyncStatus(ctx context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController, failures reconcileFailures) error {
+	status := vpa.Status.DeepCopy()
+	status.ObservedGeneration = vpa.Generation
+
+	// Drop the status of alternative recommenders that were removed.
+	status.Recommenders = slices.DeleteFunc(status.Recommenders, func(s autoscalingv1.NamedOperandStatus) bool {
+		return !slices.ContainsFunc(vpa.Spec.Recommenders, func(rec autoscalingv1.NamedRecommender) bool {
+			return rec.Name == s.Name
+		})
+	})
+	if len(status.Recommenders) == 0 {
+		status.Recommenders = nil
+	}
+
+	var unavailable, progressing, degraded []string
+	for _, params := range OperandParams(vpa) {
+		operand := params.StatusMethod(status)
+		if err := r.syncOperandStatus(ctx, vpa, params, operand, failures); err != nil {
+			return err
+		}
+
+		if params.EnabledMethod(r, vpa) && !meta.IsStatusConditionTrue(operand.Conditions, autoscalingv1.ConditionAvailable) {
+			unavailable = append(unavailable, params.AppName)
+		}
+		if meta.IsStatusConditionTrue(operand.Conditions, autoscalingv1.ConditionProgressing) {
+			progressing = append(progressing, params.AppName)
+		}
+		if meta.IsStatusConditionTrue(operand.Conditions, autoscalingv1.ConditionDegraded) {
+			degraded = append(degraded, params.AppName)
+		}
+	}
+
+	available := metav1.Condition{
+		Type:   autoscalingv1.ConditionAvailable,
+		Status: metav1.ConditionTrue,
+		Reason: ReasonAsExpected,
+	}
+	if len(unavailable) > 0 {
+		available.Status = metav1.ConditionFalse
+		available.Reason = ReasonOperandsUnavailable
+		available.Message = fmt.Sprintf("Unavailable operands: %s", strings.Join(unavailable, ", "))
+	}
+	r.setCondition(vpa, &status.Conditions, available)
+
+	progressingCond := metav1.Condition{
+		Type:   autoscalingv1.ConditionProgressing,
+		Status: metav1.ConditionFalse,
+		Reason: ReasonAsExpected,
+	}
+	if len(progressing) > 0 {
+		progressingCond.Status = metav1.ConditionTrue
+		progressingCond.Reason = ReasonOperandsProgressing
+		progressingCond.Message = fmt.Sprintf("Progressing operands: %s", strings.Join(progressing, ", "))
+	}
+	r.setCondition(vpa, &status.Conditions, progressingCond)
+
+	degradedCond := metav1.Condition{
+		Type:   autoscalingv1.ConditionDegraded,
+		Status: metav1.ConditionFalse,
+		Reason: ReasonAsExpected,
+	}
+	if f, ok := failures.operand(""); ok {
+		degradedCond.Status = metav1.ConditionTrue
+		degradedCond.Reason = f.Reason
+		degradedCond.Message = f.Message
+	} else if len(degraded) > 0 {
+		degradedCond.Status = metav1.ConditionTrue
+		degradedCond.Reason = ReasonOperandsDegraded
+		degradedCond.Message = fmt.Sprintf("Degraded operands: %s", strings.Join(degraded, ", "))
+	}
+	r.setCondition(vpa, &status.Conditions, degradedCond)
+
+	// Each resource that failed to reconcile has a condition of its own, which is
+	// removed once it reconciles again.
+	failed := map[string]bool{}
+	for _, f := range failures {
+		c := metav1.Condition{
+			Type:    f.conditionType(),
+			Status:  metav1.ConditionTrue,
+			Reason:  f.Reason,
+			Message: f.Message,
+		}
+		r.setCondition(vpa, &status.Conditions, c)
+		failed[c.Type] = true
+	}
+	status.Conditions = slices.DeleteFunc(status.Conditions, func(c metav1.Condition) bool {
+		return strings.HasPrefix(c.Type, autoscalingv1.ConditionReconcileFailedPrefix) && !failed[c.Type]
+	})
+
+	status.StaleObjects = nil
+	if vpa.Spec.Pruning == autoscalingv1.PruningDryRun {
+		stale, err := r.StaleObjects(ctx, vpa)
+		if err != nil {
+			return err
+		}
+		status.StaleObjects = stale
+	}
+
+	status.CoveredNamespaceCount = CoveredNamespaceCount(vpa, r.Config)
+	status.IgnoredNamespaces = IgnoredNamespaces(vpa, r.Config)
+
+	if r.Config.ExtraArgs == "" {
+		meta.RemoveStatusCondition(&status.Conditions, autoscalingv1.ConditionExtraArgsValid)
+	} else {
+		extraArgsCond := metav1.Condition{
+			Type:   autoscalingv1.ConditionExtraArgsValid,
+			Status: metav1.ConditionTrue,
+			Reason: ReasonAsExpected,
+		}
+		if _, err := ParseExtraArgs(r.Config.ExtraArgs); err != nil {
+			extraArgsCond.Status = metav1.ConditionFalse
+			extraArgsCond.Reason = ReasonExtraArgsInvalid
+			extraArgsCond.Message = fmt.Sprintf("Ignoring VERTICAL_POD_AUTOSCALER_EXTRA_ARGS: %v", err)
+		}
+		r.setCondition(vpa, &status.Conditions, extraArgsCond)
+	}
+
+	if value, ok := vpa.Annotations[DebugSessionAnnotation]; !ok {
+		meta.RemoveStatusCondition(&status.Conditions, autoscalingv1.ConditionDebugSession)
+	} else {
+		debugSessionCond := metav1.Condition{
+			Type:   autoscalingv1.ConditionDebugSession,
+			Status: metav1.ConditionTrue,
+			Reason: ReasonDebugSessionActive,
+		}
+		if session, err := ParseDebugSession(value); err != nil {
+			debugSessionCond.Status = metav1.ConditionFalse
+			debugSessionCond.Reason = ReasonDebugSessionInvalid
+			debugSessionCond.Message = fmt.Sprintf("Ignoring the %s annotation: %v", DebugSessionAnnotation, err)
+		} else if started, ok := debugSessionStart(vpa); ok {
+			debugSessionCond.Message = fmt.Sprintf("Verbosity of the %s raised to %d until %s", session.Command, session.Verbosity, started.Add(session.TTL).UTC().Format(time.RFC3339))
+		}
+		r.setCondition(vpa, &status.Conditions, debugSessionCond)
+	}
+
+	if equality.Semantic.DeepEqual(&vpa.Status, status) {
+		return nil
+	}
+
+	vpa.Status = *status
+	return r.Status().Update(ctx, vpa)
+}
+
+// syncOperandStatus updates the given operand status from the operand's
+// deployment and any failure recorded for it.
+func (r *VerticalPodAutoscalerControllerReconciler) syncOperandStatus(ctx context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams, operand *autoscalingv1.OperandStatus, failures reconcileFailures) error {
+	available := metav1.Condition{
+		Type:   autoscalingv1.ConditionAvailable,
+		Status: metav1.ConditionTrue,
+		Reason: ReasonAsExpected,
+	}
+	progressing := metav1.Condition{
+		Type:   autoscalingv1.ConditionProgressing,
+		Status: metav1.ConditionFalse,
+		Reason: ReasonAsExpected,
+	}
+	degraded := metav1.Condition{
+		Type:   autoscalingv1.ConditionDegraded,
+		Status: metav1.ConditionFalse,
+		Reason: ReasonAsExpected,
+	}
+
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, params.NameMethod(r, vpa), deployment)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+
+	switch {
+	case !params.EnabledMethod(r, vpa):
+		// The deployment of a disabled operand is removed, so it is not
+		// expected to be found.
+		available.Status = metav1.ConditionFalse
+		available.Reason = ReasonDisabled
+		available.Message = fmt.Sprintf("%s is disabled", params.AppName)
+		if found && deployment.Status.Replicas > 0 {
+			progressing.Status = metav1.ConditionTrue
+			progressing.Reason = ReasonDeploymentScalingDown
+			progressing.Message = fmt.Sprintf("Deployment %s is scaling down", deployment.Name)
+		}
+	case !found:
+		available.Status = metav1.ConditionFalse
+		available.Reason = ReasonDeploymentNotFound
+		available.Message = fmt.Sprintf("Deployment %s not found", params.NameMethod(r, vpa))
+	default:
+		if deployment.Status.AvailableReplicas == 0 {
+			available.Status = metav1.ConditionFalse
+			available.Reason = ReasonDeploymentUnavailable
+			available.Message = fmt.Sprintf("Deployment %s has no available replicas", deployment.Name)
+		}
+		if !util.ReleaseVersionMatches(deployment, r.Config.ReleaseVersion) || !util.DeploymentUpdated(deployment) {
+			progressing.Status = metav1.ConditionTrue
+			progressing.Reason = ReasonDeploymentUpdating
+			progressing.Message = fmt.Sprintf("Deployment %s is rolling out", deployment.Name)
+		} else {
+			// Only report what has actually been rolled out.
+			operand.ReleaseVersion = r.Config.ReleaseVersion
+			if len(deployment.Spec.Template.Spec.Containers) > 0 {
+				operand.Image = deployment.Spec.Template.Spec.Containers[0].Image
+				// An image overridden in the spec is not part of the release.
+				if operand.Image != r.Config.Image {
+					operand.ReleaseVersion = ""
+				}
+				operand.FeatureGates = enabledFeatureGates(deployment.Spec.Template.Spec.Containers[0].Args)
+			}
+		}
+	}
+
+	if found {
+		for _, c := range deployment.Status.Conditions {
+			switch {
+			case c.Type == appsv1.DeploymentReplicaFailure && c.Status == corev1.ConditionTrue:
+				degraded.Status = metav1.ConditionTrue
+				degraded.Reason = ReasonReplicaFailure
+				degraded.Message = c.Message
+			case c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse && c.Reason == "ProgressDeadlineExceeded":
+				degraded.Status = metav1.ConditionTrue
+				degraded.Reason = ReasonProgressDeadlineExceeded
+				degraded.Message = c.Message
+			}
+		}
+	}
+
+	// A failure to reconcile the operand takes precedence over what the
+	// deployment reports, since the deployment may not reflect the spec.
+	if f, ok := failures.operand(params.AppName); ok {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = f.Reason
+		degraded.Message = f.Message
+	}
+
+	if degraded.Status == metav1.ConditionTrue {
+		if operand.LastFailure == nil || operand.LastFailure.Reason != degraded.Reason || operand.LastFailure.Message != degraded.Message {
+			operand.LastFailure = &autoscalingv1.OperandFailure{
+				Reason:  degraded.Reason,
+				Message: degraded.Message,
+				Time:    metav1.Now(),
+			}
+		}
+	}
+
+	r.setCondition(vpa, &operand.Conditions, available)
+	r.setCondition(vpa, &operand.Conditions, progressing)
+	r.setCondition(vpa, &operand.Conditions, degraded)
+
+	return nil
+}
+
+// enabledFeatureGates returns the feature gates enabled by the given command
+// line arguments, sorted by name.
+func enabledFeatureGates(args []string) []string {
+	// Like the operands, merge the gates of repeated flags.
+	gates := map[string]string{}
+	for _, arg := range util.ParseArgs(args) {
+		if arg.Name == util.ArgName(FeatureGatesArg.String()) {
+			maps.Copy(gates, util.ParseMapValue(arg.Value))
+		}
+	}
+
+	var enabled []string
+	for _, gate := range slices.Sorted(maps.Keys(gates)) {
+		if on, err := strconv.ParseBool(gates[gate]); err == nil && on {
+			enabled = append(enabled, gate)
+		}
+	}
+	return enabled
+}
+
+// setCondition sets the given condition, stamping it with the generation of the
+// VerticalPodAutoscalerController it was computed from.
+func (r *VerticalPodAutoscalerControllerReconciler) setCondition(vpa *autoscalingv1.VerticalPodAutoscalerController, conditions *[]metav1.Condition, condition metav1.Condition) {
+	condition.ObservedGeneration = vpa.Generation
+	meta.SetStatusCondition(conditions, condition)
+}
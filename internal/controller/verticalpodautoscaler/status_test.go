@@ -0,0 +1,390 @@
+package verticalpodautoscaler
+
+import (
+	"context"
+	"testing"
+
+	autoscalingv1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
+	"github.com/openshift/vertical-pod-autoscaler-operator/internal/util"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// availableDeployment returns the expected deployment for the given operand,
+// with a status reporting it as fully rolled out and available.
+func availableDeployment(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) *appsv1.Deployment {
+	dep := r.AutoscalerDeployment(vpa, params)
+	dep.Generation = 1
+	dep.Status = appsv1.DeploymentStatus{
+		ObservedGeneration: 1,
+		Replicas:           *dep.Spec.Replicas,
+		UpdatedReplicas:    *dep.Spec.Replicas,
+		AvailableReplicas:  *dep.Spec.Replicas,
+	}
+	return dep
+}
+
+func TestSyncStatus(t *testing.T) {
+	recommendationOnly := true
+
+	testCases := []struct {
+		label    string
+		mutate   func(vpa *autoscalingv1.VerticalPodAutoscalerController)
+		objects  func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController) []runtime.Object
+		failures reconcileFailures
+		// expected top level conditions
+		available   metav1.ConditionStatus
+		progressing metav1.ConditionStatus
+		degraded    metav1.ConditionStatus
+		check       func(t *testing.T, status *autoscalingv1.VerticalPodAutoscalerControllerStatus)
+	}{
+		{
+			label:       "no deployments",
+			available:   metav1.ConditionFalse,
+			progressing: metav1.ConditionFalse,
+			degraded:    metav1.ConditionFalse,
+			check: func(t *testing.T, status *autoscalingv1.VerticalPodAutoscalerControllerStatus) {
+				cond := meta.FindStatusCondition(status.Recommender.Conditions, autoscalingv1.ConditionAvailable)
+				assert.Equal(t, ReasonDeploymentNotFound, cond.Reason)
+				assert.Empty(t, status.Recommender.Image)
+			},
+		},
+		{
+			label: "all operands available",
+			objects: func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController) []runtime.Object {
+				var objs []runtime.Object
+				for _, params := range controllerParams {
+					objs = append(objs, availableDeployment(r, vpa, params))
+				}
+				return objs
+			},
+			available:   metav1.ConditionTrue,
+			progressing: metav1.ConditionFalse,
+			degraded:    metav1.ConditionFalse,
+			check: func(t *testing.T, status *autoscalingv1.VerticalPodAutoscalerControllerStatus) {
+				for _, operand := range []autoscalingv1.OperandStatus{status.Recommender, status.Updater, status.Admission} {
+					assert.Equal(t, TestReconcilerConfig.Image, operand.Image)
+					assert.Equal(t, TestReconcilerConfig.ReleaseVersion, operand.ReleaseVersion)
+					assert.True(t, meta.IsStatusConditionTrue(operand.Conditions, autoscalingv1.ConditionAvailable))
+					assert.Nil(t, operand.LastFailure)
+				}
+			},
+		},
+		{
+			label: "old release version is progressing",
+			objects: func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController) []runtime.Object {
+				var objs []runtime.Object
+				for _, params := range controllerParams {
+					dep := availableDeployment(r, vpa, params)
+					dep.Annotations[util.ReleaseVersionAnnotation] = "vOLD"
+					objs = append(objs, dep)
+				}
+				return objs
+			},
+			available:   metav1.ConditionTrue,
+			progressing: metav1.ConditionTrue,
+			degraded:    metav1.ConditionFalse,
+			check: func(t *testing.T, status *autoscalingv1.VerticalPodAutoscalerControllerStatus) {
+				assert.Empty(t, status.Updater.ReleaseVersion)
+				cond := meta.FindStatusCondition(status.Updater.Conditions, autoscalingv1.ConditionProgressing)
+				assert.Equal(t, ReasonDeploymentUpdating, cond.Reason)
+			},
+		},
+		{
+			label: "overridden image is not at the release version",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.DeploymentOverrides.Recommender.Container.Image = "mirror.example.com/vpa:patched"
+			},
+			objects: func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController) []runtime.Object {
+				var objs []runtime.Object
+				for _, params := range controllerParams {
+					objs = append(objs, availableDeployment(r, vpa, params))
+				}
+				return objs
+			},
+			available:   metav1.ConditionTrue,
+			progressing: metav1.ConditionFalse,
+			degraded:    metav1.ConditionFalse,
+			check: func(t *testing.T, status *autoscalingv1.VerticalPodAutoscalerControllerStatus) {
+				assert.Equal(t, "mirror.example.com/vpa:patched", status.Recommender.Image)
+				assert.Empty(t, status.Recommender.ReleaseVersion)
+				assert.Equal(t, TestReconcilerConfig.ReleaseVersion, status.Updater.ReleaseVersion)
+			},
+		},
+		{
+			label: "enabled feature gates are reported",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{FeatureGates: map[string]bool{"InPlaceOrRecreate": true}}
+				vpa.Spec.Admission = &autoscalingv1.AdmissionConfig{FeatureGates: map[string]bool{"InPlaceOrRecreate": false}}
+				vpa.Spec.DeploymentOverrides.Updater.Container.Args = []string{"--feature-gates=Other=true"}
+			},
+			objects: func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController) []runtime.Object {
+				var objs []runtime.Object
+				for _, params := range controllerParams {
+					objs = append(objs, availableDeployment(r, vpa, params))
+				}
+				return objs
+			},
+			available:   metav1.ConditionTrue,
+			progressing: metav1.ConditionFalse,
+			degraded:    metav1.ConditionFalse,
+			check: func(t *testing.T, status *autoscalingv1.VerticalPodAutoscalerControllerStatus) {
+				assert.Equal(t, []string{"InPlaceOrRecreate", "Other"}, status.Updater.FeatureGates)
+				assert.Empty(t, status.Admission.FeatureGates)
+				assert.Empty(t, status.Recommender.FeatureGates)
+			},
+		},
+		{
+			label: "recommendation only ignores disabled operands",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.RecommendationOnly = &recommendationOnly
+			},
+			objects: func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController) []runtime.Object {
+				var objs []runtime.Object
+				for _, params := range controllerParams {
+					objs = append(objs, availableDeployment(r, vpa, params))
+				}
+				return objs
+			},
+			available:   metav1.ConditionTrue,
+			progressing: metav1.ConditionFalse,
+			degraded:    metav1.ConditionFalse,
+			check: func(t *testing.T, status *autoscalingv1.VerticalPodAutoscalerControllerStatus) {
+				cond := meta.FindStatusCondition(status.Updater.Conditions, autoscalingv1.ConditionAvailable)
+				assert.Equal(t, metav1.ConditionFalse, cond.Status)
+				assert.Equal(t, ReasonDisabled, cond.Reason)
+			},
+		},
+		{
+			label: "removed operands are disabled",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{Enabled: ptr.To(false)}
+			},
+			objects: func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController) []runtime.Object {
+				return []runtime.Object{
+					availableDeployment(r, vpa, controllerParams[0]),
+					availableDeployment(r, vpa, controllerParams[2]),
+				}
+			},
+			available:   metav1.ConditionTrue,
+			progressing: metav1.ConditionFalse,
+			degraded:    metav1.ConditionFalse,
+			check: func(t *testing.T, status *autoscalingv1.VerticalPodAutoscalerControllerStatus) {
+				cond := meta.FindStatusCondition(status.Updater.Conditions, autoscalingv1.ConditionAvailable)
+				assert.Equal(t, metav1.ConditionFalse, cond.Status)
+				assert.Equal(t, ReasonDisabled, cond.Reason)
+			},
+		},
+		{
+			label: "replica failure is degraded",
+			objects: func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController) []runtime.Object {
+				var objs []runtime.Object
+				for _, params := range controllerParams {
+					dep := availableDeployment(r, vpa, params)
+					if params.AppName == AdmissionControllerAppName {
+						dep.Status.Conditions = []appsv1.DeploymentCondition{
+							{
+								Type:    appsv1.DeploymentReplicaFailure,
+								Status:  corev1.ConditionTrue,
+								Message: "exceeded quota",
+							},
+						}
+					}
+					objs = append(objs, dep)
+				}
+				return objs
+			},
+			available:   metav1.ConditionTrue,
+			progressing: metav1.ConditionFalse,
+			degraded:    metav1.ConditionTrue,
+			check: func(t *testing.T, status *autoscalingv1.VerticalPodAutoscalerControllerStatus) {
+				if assert.NotNil(t, status.Admission.LastFailure) {
+					assert.Equal(t, ReasonReplicaFailure, status.Admission.LastFailure.Reason)
+					assert.Equal(t, "exceeded quota", status.Admission.LastFailure.Message)
+				}
+				assert.False(t, meta.IsStatusConditionTrue(status.Recommender.Conditions, autoscalingv1.ConditionDegraded))
+			},
+		},
+		{
+			label: "reconcile failure is degraded",
+			objects: func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController) []runtime.Object {
+				var objs []runtime.Object
+				for _, params := range controllerParams {
+					objs = append(objs, availableDeployment(r, vpa, params))
+				}
+				return objs
+			},
+			failures: reconcileFailures{
+				{Operand: "vpa-recommender", Kind: "Deployment", Name: "vpa-recommender-test", Reason: "FailedUpdate", Message: "update denied"},
+			},
+			available:   metav1.ConditionTrue,
+			progressing: metav1.ConditionFalse,
+			degraded:    metav1.ConditionTrue,
+			check: func(t *testing.T, status *autoscalingv1.VerticalPodAutoscalerControllerStatus) {
+				cond := meta.FindStatusCondition(status.Recommender.Conditions, autoscalingv1.ConditionDegraded)
+				assert.Equal(t, "FailedUpdate", cond.Reason)
+				assert.Equal(t, "update denied", cond.Message)
+				cond = meta.FindStatusCondition(status.Conditions, "ReconcileFailed.deployment.vpa-recommender-test")
+				if assert.NotNil(t, cond) {
+					assert.Equal(t, metav1.ConditionTrue, cond.Status)
+					assert.Equal(t, "update denied", cond.Message)
+				}
+			},
+		},
+		{
+			label: "reconcile failure of a leader-locking role",
+			objects: func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController) []runtime.Object {
+				var objs []runtime.Object
+				for _, params := range controllerParams {
+					objs = append(objs, availableDeployment(r, vpa, params))
+				}
+				return objs
+			},
+			failures: reconcileFailures{
+				{Kind: "Role", Name: "system:leader-locking-vpa-recommender-test", Reason: "FailedCreate", Message: "create denied"},
+			},
+			available:   metav1.ConditionTrue,
+			progressing: metav1.ConditionFalse,
+			degraded:    metav1.ConditionTrue,
+			check: func(t *testing.T, status *autoscalingv1.VerticalPodAutoscalerControllerStatus) {
+				cond := meta.FindStatusCondition(status.Conditions, "ReconcileFailed.role.system_leader-locking-vpa-recommender-test")
+				if assert.NotNil(t, cond) {
+					assert.Equal(t, "create denied", cond.Message)
+				}
+				assert.Empty(t, metav1validation.ValidateConditions(status.Conditions, field.NewPath("status", "conditions")))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			vpa := NewVerticalPodAutoscaler()
+			vpa.Generation = 3
+			if tc.mutate != nil {
+				tc.mutate(vpa)
+			}
+
+			objs := []runtime.Object{vpa}
+			if tc.objects != nil {
+				objs = append(objs, tc.objects(newFakeReconciler(), vpa)...)
+			}
+			r := newFakeReconciler(objs...)
+
+			existing := &autoscalingv1.VerticalPodAutoscalerController{}
+			if err := r.Get(context.TODO(), types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}, existing); err != nil {
+				t.Fatalf("error getting VerticalPodAutoscalerController: %v", err)
+			}
+
+			if err := r.SyncStatus(context.TODO(), existing, tc.failures); err != nil {
+				t.Fatalf("error syncing status: %v", err)
+			}
+
+			got := &autoscalingv1.VerticalPodAutoscalerController{}
+			if err := r.Get(context.TODO(), types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}, got); err != nil {
+				t.Fatalf("error getting VerticalPodAutoscalerController: %v", err)
+			}
+
+			assert.Equal(t, int64(3), got.Status.ObservedGeneration)
+			for condType, want := range map[string]metav1.ConditionStatus{
+				autoscalingv1.ConditionAvailable:   tc.available,
+				autoscalingv1.ConditionProgressing: tc.progressing,
+				autoscalingv1.ConditionDegraded:    tc.degraded,
+			} {
+				cond := meta.FindStatusCondition(got.Status.Conditions, condType)
+				if assert.NotNil(t, cond, "missing condition %s", condType) {
+					assert.Equal(t, want, cond.Status, "wrong status for condition %s", condType)
+					assert.Equal(t, int64(3), cond.ObservedGeneration)
+				}
+			}
+
+			if tc.check != nil {
+				tc.check(t, &got.Status)
+			}
+		})
+	}
+}
+
+func TestReconcileReportsStatus(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	r := newFakeReconciler(vpa)
+	nn := types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}
+
+	if _, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: nn}); err != nil {
+		t.Fatalf("unexpected error reconciling: %v", err)
+	}
+
+	got := &autoscalingv1.VerticalPodAutoscalerController{}
+	if err := r.Get(context.TODO(), nn, got); err != nil {
+		t.Fatalf("error getting VerticalPodAutoscalerController: %v", err)
+	}
+
+	// The deployments were just created, so none are available yet.
+	for _, operand := range []autoscalingv1.OperandStatus{got.Status.Recommender, got.Status.Updater, got.Status.Admission} {
+		cond := meta.FindStatusCondition(operand.Conditions, autoscalingv1.ConditionAvailable)
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, ReasonDeploymentUnavailable, cond.Reason)
+		}
+	}
+	assert.False(t, meta.IsStatusConditionTrue(got.Status.Conditions, autoscalingv1.ConditionAvailable))
+	assert.False(t, meta.IsStatusConditionTrue(got.Status.Conditions, autoscalingv1.ConditionDegraded))
+}
+
+func TestSyncStatusExtraArgs(t *testing.T) {
+	testCases := []struct {
+		label     string
+		extraArgs string
+		expected  *metav1.Condition
+	}{
+		{
+			label: "no extra args",
+		},
+		{
+			label:     "valid extra args",
+			extraArgs: `--v=4 recommender:--memory-saver`,
+			expected:  &metav1.Condition{Status: metav1.ConditionTrue, Reason: ReasonAsExpected},
+		},
+		{
+			label:     "unterminated quote",
+			extraArgs: `--v="4`,
+			expected:  &metav1.Condition{Status: metav1.ConditionFalse, Reason: ReasonExtraArgsInvalid},
+		},
+		{
+			label:     "url value",
+			extraArgs: `--prometheus-address https://prometheus:9091`,
+			expected:  &metav1.Condition{Status: metav1.ConditionTrue, Reason: ReasonAsExpected},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			vpa := NewVerticalPodAutoscaler()
+			r := newFakeReconciler(vpa)
+			cfg := *TestReconcilerConfig
+			cfg.ExtraArgs = tc.extraArgs
+			r.Config = &cfg
+
+			if err := r.SyncStatus(context.TODO(), vpa, nil); err != nil {
+				t.Fatalf("error syncing status: %v", err)
+			}
+
+			cond := meta.FindStatusCondition(vpa.Status.Conditions, autoscalingv1.ConditionExtraArgsValid)
+			if tc.expected == nil {
+				assert.Nil(t, cond)
+				return
+			}
+			if assert.NotNil(t, cond) {
+				assert.Equal(t, tc.expected.Status, cond.Status)
+				assert.Equal(t, tc.expected.Reason, cond.Reason)
+			}
+		})
+	}
+}
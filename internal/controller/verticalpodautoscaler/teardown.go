@@ -0,0 +1,115 @@
+package verticalpodautoscaler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	autoscalingv1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
+)
+
+// TeardownFinalizer holds a VerticalPodAutoscalerController that is being
+// deleted until the cluster-scoped objects left by its operands are removed.
+// The namespaced objects are garbage collected through their owner references
+// once it is released.
+const TeardownFinalizer = "autoscaling.openshift.io/teardown"
+
+// AdmissionScaleDownTimeout is how long the teardown waits for the pods of the
+// admission controller to go away before it deletes the webhook configuration
+// all the same, so a pod stuck terminating does not block the deletion.
+const AdmissionScaleDownTimeout = 2 * time.Minute
+
+// admissionScaleDownInterval is how often the teardown checks whether the
+// admission controller has scaled down.
+const admissionScaleDownInterval = 5 * time.Second
+
+// ensureTeardownFinalizer adds the TeardownFinalizer to the given
+// VerticalPodAutoscalerController if it does not have it yet.
+func (r *VerticalPodAutoscalerControllerReconciler) ensureTeardownFinalizer(ctx context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController) error {
+	if !controllerutil.AddFinalizer(vpa, TeardownFinalizer) {
+		return nil
+	}
+	return r.Update(ctx, vpa)
+}
+
+// teardown removes the cluster-scoped objects left by the operands of the given
+// VerticalPodAutoscalerController, which is being deleted, and then releases
+// it.  The admission controller is scaled down first, so it does not register
+// its webhook again once the configuration is deleted.
+func (r *VerticalPodAutoscalerControllerReconciler) teardown(ctx context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController, vpaRef *corev1.ObjectReference) (reconcile.Result, error) {
+	if !controllerutil.ContainsFinalizer(vpa, TeardownFinalizer) {
+		return reconcile.Result{}, nil
+	}
+
+	updated, scaledDown, err := r.ScaleDownAdmissionPlugin(ctx, vpa)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error scaling down vertical-pod-autoscaler admission controller %s: %v", r.AdmissionPluginName(vpa).Name, err)
+		r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedUpdate", "Update", "%s", errMsg)
+		klog.Error(errMsg)
+		return reconcile.Result{}, err
+	} else if updated {
+		msg := fmt.Sprintf("Scaled down VerticalPodAutoscalerController Deployment: %s", r.AdmissionPluginName(vpa).Name)
+		r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeNormal, "SuccessfulUpdate", "Update", "%s", msg)
+		klog.Info(msg)
+	}
+	if !scaledDown {
+		if waited := time.Since(vpa.DeletionTimestamp.Time); waited < AdmissionScaleDownTimeout {
+			klog.Infof("Waiting for VerticalPodAutoscalerController deployment %s to scale down", r.AdmissionPluginName(vpa).Name)
+			return reconcile.Result{RequeueAfter: min(admissionScaleDownInterval, AdmissionScaleDownTimeout-waited)}, nil
+		}
+		klog.Warningf("VerticalPodAutoscalerController deployment %s did not scale down within %s", r.AdmissionPluginName(vpa).Name, AdmissionScaleDownTimeout)
+	}
+
+	deleted, err := r.DeleteWebhookConfig(ctx)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error deleting vertical-pod-autoscaler webhook configuration %s: %v", WebhookConfigName, err)
+		r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedDelete", "Delete", "%s", errMsg)
+		klog.Error(errMsg)
+		return reconcile.Result{}, err
+	}
+	if deleted {
+		msg := fmt.Sprintf("Deleted VerticalPodAutoscalerController webhook configuration: %s", WebhookConfigName)
+		r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeNormal, "SuccessfulDelete", "Delete", "%s", msg)
+		klog.Info(msg)
+	}
+
+	controllerutil.RemoveFinalizer(vpa, TeardownFinalizer)
+	if err := r.Update(ctx, vpa); err != nil {
+		klog.Errorf("Error removing the finalizer of VerticalPodAutoscalerController %s: %v", vpa.Name, err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// ScaleDownAdmissionPlugin scales the admission controller deployment of the
+// given VerticalPodAutoscalerController down to zero replicas.  It returns
+// whether it was updated, and whether all of its pods are gone.
+func (r *VerticalPodAutoscalerControllerReconciler) ScaleDownAdmissionPlugin(ctx context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController) (bool, bool, error) {
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, r.AdmissionPluginName(vpa), deployment); err != nil {
+		if errors.IsNotFound(err) {
+			return false, true, nil
+		}
+		return false, false, err
+	}
+
+	updated := false
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 0 {
+		original := deployment.DeepCopy()
+		deployment.Spec.Replicas = ptr.To[int32](0)
+		if err := r.Patch(ctx, deployment, client.MergeFrom(original)); err != nil {
+			return false, false, err
+		}
+		updated = true
+	}
+	return updated, deployment.Status.Replicas == 0, nil
+}
@@ -23,7 +23,13 @@ func (a UpdaterArg) Value(v interface{}) string {
 // These constants represent the vertical-pod-autoscaler arguments used by the
 // operator when processing VerticalPodAutoscalerController resources.
 const (
-	MinReplicasArg UpdaterArg = "--min-replicas"
+	MinReplicasArg                                     UpdaterArg = "--min-replicas"
+	EvictionToleranceArg                               UpdaterArg = "--eviction-tolerance"
+	EvictionRateLimitArg                               UpdaterArg = "--eviction-rate-limit"
+	EvictionRateBurstArg                               UpdaterArg = "--eviction-rate-burst"
+	UpdaterIntervalArg                                 UpdaterArg = "--updater-interval"
+	EvictAfterOOMThresholdArg                          UpdaterArg = "--evict-after-oom-threshold"
+	InRecommendationBoundsEvictionLifetimeThresholdArg UpdaterArg = "--in-recommendation-bounds-eviction-lifetime-threshold"
 )
 
 // UpdaterArgs returns a slice of strings representing command line arguments
@@ -32,12 +38,37 @@ const (
 func UpdaterArgs(vpa *v1.VerticalPodAutoscalerController, cfg *Config) []string {
 	s := &vpa.Spec
 
-	args := []string{
-		LogToStderrArg.String(),
-		VerbosityArg.Value(cfg.Verbosity),
+	var logging *v1.LoggingConfig
+	if s.Updater != nil {
+		logging = s.Updater.Logging
 	}
+	args := loggingArgs(logging, cfg)
+	args = append(args, leaderElectionArgs(s.DeploymentOverrides.Updater.Replicas, UpdaterLeaseName, cfg)...)
+	args = append(args, ignoredNamespacesArgs(vpa, cfg)...)
 	if s.MinReplicas != nil {
 		args = append(args, MinReplicasArg.Value(*s.MinReplicas))
 	}
+
+	if c := s.Updater; c != nil {
+		if c.EvictionTolerance != nil {
+			args = append(args, EvictionToleranceArg.Value(*c.EvictionTolerance))
+		}
+		if c.EvictionRateLimit != nil {
+			args = append(args, EvictionRateLimitArg.Value(*c.EvictionRateLimit))
+		}
+		if c.EvictionRateBurst != nil {
+			args = append(args, EvictionRateBurstArg.Value(*c.EvictionRateBurst))
+		}
+		if c.UpdaterInterval != nil {
+			args = append(args, UpdaterIntervalArg.Value(c.UpdaterInterval.Duration))
+		}
+		if c.EvictAfterOOMThreshold != nil {
+			args = append(args, EvictAfterOOMThresholdArg.Value(c.EvictAfterOOMThreshold.Duration))
+		}
+		if c.InRecommendationBoundsEvictionLifetimeThreshold != nil {
+			args = append(args, InRecommendationBoundsEvictionLifetimeThresholdArg.Value(c.InRecommendationBoundsEvictionLifetimeThreshold.Duration))
+		}
+		args = append(args, featureGatesArgs(c.FeatureGates)...)
+	}
 	return args
 }
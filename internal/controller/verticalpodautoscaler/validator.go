@@ -1,24 +1,560 @@
 package verticalpodautoscaler
 
 import (
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	configv1 "github.com/openshift/api/config/v1"
+	autoscalingv1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
+	"github.com/openshift/vertical-pod-autoscaler-operator/internal/util"
+)
+
+// MaxSafetyMarginFraction is the largest safety margin fraction accepted by
+// the validating webhook.
+const MaxSafetyMarginFraction = float64(1)
+
+// Pod labels, pod annotations and container env vars set by the operator,
+// which the deployment overrides must not replace.
+var (
+	reservedPodLabels      = sets.New("app", "vertical-pod-autoscaler")
+	reservedPodAnnotations = sets.New(util.ReleaseVersionAnnotation)
+	reservedEnvVars        = sets.New("NAMESPACE", "SSL_CERT_DIR")
+)
+
+// Arguments tying the operands to their VerticalPodAutoscalerController, which
+// the deployment overrides must neither replace nor remove.
+var reservedArgs = sets.New(
+	util.ArgName(RecommenderNameArg.String()),
+	util.ArgName(IgnoredVPAObjectNamespacesArg.String()),
+	util.ArgName(LeaderElectResourceNameArg.String()),
+	util.ArgName(LeaderElectResourceNamespaceArg.String()),
+)
+
+var supportedTolerationOperators = sets.New(
+	string(corev1.TolerationOpEqual),
+	string(corev1.TolerationOpExists),
+)
+
+var supportedTaintEffects = sets.New(
+	string(corev1.TaintEffectNoSchedule),
+	string(corev1.TaintEffectPreferNoSchedule),
+	string(corev1.TaintEffectNoExecute),
 )
 
 // Validator validates VerticalPodAutoscalerController resources.
 type Validator struct {
-	client  client.Client
-	decoder *admission.Decoder
+	// Config is the reconciler's config, used to determine the expected
+	// resource name and the arguments set by the operator.
+	Config *Config
+}
+
+var _ admission.Validator[*autoscalingv1.VerticalPodAutoscalerController] = &Validator{}
+
+// ValidateCreate validates a VerticalPodAutoscalerController on creation.
+func (v *Validator) ValidateCreate(_ context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController) (admission.Warnings, error) {
+	return nil, v.Validate(vpa)
+}
+
+// ValidateUpdate validates a VerticalPodAutoscalerController on update.  The
+// spec is only validated, as a whole, when it changed, and the debug session
+// annotations when they changed, so that tightened validations do not block
+// metadata-only updates of existing resources, such as label edits or the
+// updates made by the operator itself.  Resources being deleted are not
+// validated at all, so nothing can hold up their teardown.
+func (v *Validator) ValidateUpdate(_ context.Context, old, vpa *autoscalingv1.VerticalPodAutoscalerController) (admission.Warnings, error) {
+	if vpa.DeletionTimestamp != nil {
+		return nil, nil
+	}
+
+	var allErrs field.ErrorList
+
+	if value, ok := vpa.Annotations[DebugSessionAnnotation]; ok && value != old.Annotations[DebugSessionAnnotation] {
+		allErrs = append(allErrs, validateDebugSession(vpa)...)
+	}
+
+	if value, ok := vpa.Annotations[DebugSessionStartedAnnotation]; ok && value != old.Annotations[DebugSessionStartedAnnotation] {
+		allErrs = append(allErrs, validateDebugSessionStart(vpa)...)
+	}
+
+	if !equality.Semantic.DeepEqual(old.Spec, vpa.Spec) {
+		allErrs = append(allErrs, v.validateSpec(vpa)...)
+	}
+
+	return nil, invalidError(vpa, allErrs)
+}
+
+// ValidateDelete allows every VerticalPodAutoscalerController to be deleted.
+func (v *Validator) ValidateDelete(_ context.Context, _ *autoscalingv1.VerticalPodAutoscalerController) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// Validate returns an Invalid error listing everything wrong with the given
+// VerticalPodAutoscalerController, or nil if it is valid.
+func (v *Validator) Validate(vpa *autoscalingv1.VerticalPodAutoscalerController) error {
+	var allErrs field.ErrorList
+
+	if vpa.Name != v.Config.Name {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), vpa.Name,
+			fmt.Sprintf("the operator only manages the VerticalPodAutoscalerController named %q", v.Config.Name)))
+	}
+
+	allErrs = append(allErrs, validateDebugSession(vpa)...)
+	allErrs = append(allErrs, validateDebugSessionStart(vpa)...)
+	allErrs = append(allErrs, v.validateSpec(vpa)...)
+
+	return invalidError(vpa, allErrs)
+}
+
+// invalidError returns an Invalid error for the given
+// VerticalPodAutoscalerController listing the given errors, or nil if there are
+// none.
+func invalidError(vpa *autoscalingv1.VerticalPodAutoscalerController, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return errors.NewInvalid(autoscalingv1.GroupVersion.WithKind("VerticalPodAutoscalerController").GroupKind(), vpa.Name, allErrs)
+}
+
+// validateDebugSession checks the debug session annotation of the given
+// VerticalPodAutoscalerController, if any.
+func validateDebugSession(vpa *autoscalingv1.VerticalPodAutoscalerController) field.ErrorList {
+	value, ok := vpa.Annotations[DebugSessionAnnotation]
+	if !ok {
+		return nil
+	}
+	if _, err := ParseDebugSession(value); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("metadata", "annotations").Key(DebugSessionAnnotation), value, err.Error())}
+	}
+	return nil
+}
+
+// validateDebugSessionStart checks the start time of the debug session of the
+// given VerticalPodAutoscalerController, if any.  The operator sets it when it
+// starts the session, so a time in the future can only have been set by hand,
+// e.g. to make the session outlast its ttl.
+func validateDebugSessionStart(vpa *autoscalingv1.VerticalPodAutoscalerController) field.ErrorList {
+	value, ok := vpa.Annotations[DebugSessionStartedAnnotation]
+	if !ok {
+		return nil
+	}
+	if started, ok := debugSessionStart(vpa); !ok || started.After(time.Now()) {
+		return field.ErrorList{field.Invalid(field.NewPath("metadata", "annotations").Key(DebugSessionStartedAnnotation), value,
+			"must be a time in RFC 3339 format that is not in the future")}
+	}
+	return nil
+}
+
+// validateSpec checks the spec of the given VerticalPodAutoscalerController.
+func (v *Validator) validateSpec(vpa *autoscalingv1.VerticalPodAutoscalerController) field.ErrorList {
+	var allErrs field.ErrorList
+
+	specPath := field.NewPath("spec")
+
+	if s := vpa.Spec.SafetyMarginFraction; s != nil && (*s < 0 || *s > MaxSafetyMarginFraction) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("safetyMarginFraction"), *s,
+			fmt.Sprintf("must be between 0 and %v", MaxSafetyMarginFraction)))
+	}
+
+	if vpa.Spec.Recommender != nil {
+		allErrs = append(allErrs, validateRecommender(specPath.Child("recommender"), vpa.Spec.Recommender)...)
+	}
+
+	if vpa.Spec.Updater != nil {
+		allErrs = append(allErrs, validateUpdater(specPath.Child("updater"), vpa.Spec.Updater)...)
+	}
+
+	if vpa.Spec.Admission != nil {
+		allErrs = append(allErrs, validateAdmission(specPath.Child("admission"), vpa.Spec.Admission)...)
+	}
+
+	allErrs = append(allErrs, validateEnabled(specPath, &vpa.Spec)...)
+	allErrs = append(allErrs, validateNamespaceScope(specPath, &vpa.Spec)...)
+
+	// The TLS arguments are only set while the cluster TLS profile is
+	// honored, which can change at any time, so always treat them as set,
+	// e.g. for overrides removing them.
+	argsConfig := &Config{
+		Name:      v.Config.Name,
+		Verbosity: v.Config.Verbosity,
+		TLSProfileSpec: &configv1.TLSProfileSpec{
+			MinTLSVersion: configv1.VersionTLS12,
+			Ciphers:       configv1.TLSProfiles[configv1.TLSProfileIntermediateType].Ciphers,
+		},
+	}
+
+	overridesPath := specPath.Child("deploymentOverrides")
+	type operand struct {
+		path         *field.Path
+		override     *autoscalingv1.DeploymentOverride
+		getArgs      func(vpa *autoscalingv1.VerticalPodAutoscalerController, cfg *Config) []string
+		gatesPath    *field.Path
+		featureGates map[string]bool
+	}
+	operands := []operand{
+		{overridesPath.Child("admission"), &vpa.Spec.DeploymentOverrides.Admission, AdmissionPluginArgs, specPath.Child("admission", "featureGates"), nil},
+		{overridesPath.Child("recommender"), &vpa.Spec.DeploymentOverrides.Recommender, RecommenderArgs, specPath.Child("recommender", "featureGates"), nil},
+		{overridesPath.Child("updater"), &vpa.Spec.DeploymentOverrides.Updater, UpdaterArgs, specPath.Child("updater", "featureGates"), nil},
+	}
+	if vpa.Spec.Admission != nil {
+		operands[0].featureGates = vpa.Spec.Admission.FeatureGates
+	}
+	if vpa.Spec.Recommender != nil {
+		operands[1].featureGates = vpa.Spec.Recommender.FeatureGates
+	}
+	if vpa.Spec.Updater != nil {
+		operands[2].featureGates = vpa.Spec.Updater.FeatureGates
+	}
+	for i := range vpa.Spec.Recommenders {
+		rec := &vpa.Spec.Recommenders[i]
+		recPath := specPath.Child("recommenders").Index(i)
+		allErrs = append(allErrs, validateRecommenderName(recPath.Child("name"), rec.Name)...)
+		var featureGates map[string]bool
+		if rec.Config != nil {
+			allErrs = append(allErrs, validateRecommender(recPath.Child("config"), rec.Config)...)
+			featureGates = rec.Config.FeatureGates
+		}
+		operands = append(operands, operand{
+			path:     recPath.Child("deploymentOverride"),
+			override: &rec.DeploymentOverride,
+			getArgs: func(vpa *autoscalingv1.VerticalPodAutoscalerController, cfg *Config) []string {
+				return NamedRecommenderArgs(vpa, rec, cfg)
+			},
+			gatesPath:    recPath.Child("config", "featureGates"),
+			featureGates: featureGates,
+		})
+	}
+
+	for _, operand := range operands {
+		path := operand.path
+		allErrs = append(allErrs, validateFeatureGates(operand.gatesPath, operand.featureGates, operand.override.Container.Image)...)
+		allErrs = append(allErrs, validateOverrideArgs(path.Child("container", "args"), operand.override.Container.Args, operand.getArgs(vpa, argsConfig))...)
+		allErrs = append(allErrs, validateTolerations(path.Child("tolerations"), operand.override.Tolerations)...)
+		allErrs = append(allErrs, validatePodOverrides(path, operand.override)...)
+	}
+
+	return allErrs
+}
+
+// validateNamespaceScope checks the namespaces ignored and selected by a
+// VerticalPodAutoscalerController.
+func validateNamespaceScope(path *field.Path, spec *autoscalingv1.VerticalPodAutoscalerControllerSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	ignoredPath := path.Child("ignoredNamespaces")
+	for i, ns := range spec.IgnoredNamespaces {
+		for _, msg := range validation.IsDNS1123Label(ns) {
+			allErrs = append(allErrs, field.Invalid(ignoredPath.Index(i), ns, msg))
+		}
+	}
+	if spec.NamespaceSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(spec.NamespaceSelector, metav1validation.LabelSelectorValidationOptions{}, path.Child("namespaceSelector"))...)
+
+		// The selector only scopes the webhook, so the updater would keep evicting
+		// the pods of the namespaces it does not select without them ever being
+		// updated.
+		if spec.UpdaterEnabled() {
+			allErrs = append(allErrs, field.Forbidden(path.Child("namespaceSelector"),
+				"may not be set while the updater is enabled, list the namespaces to leave alone in ignoredNamespaces instead"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateRecommender checks the fields of the recommender config that cannot
+// be checked by the CRD schema.
+func validateRecommender(path *field.Path, c *autoscalingv1.RecommenderConfig) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateLogging(path.Child("logging"), c.Logging)...)
+
+	durations := []struct {
+		name  string
+		value *metav1.Duration
+	}{
+		{"cpuHistogramDecayHalfLife", c.CPUHistogramDecayHalfLife},
+		{"memoryHistogramDecayHalfLife", c.MemoryHistogramDecayHalfLife},
+		{"recommenderInterval", c.RecommenderInterval},
+		{"checkpointsGCInterval", c.CheckpointsGCInterval},
+	}
+	for _, d := range durations {
+		allErrs = append(allErrs, validatePositiveDuration(path.Child(d.name), d.value)...)
+	}
+
+	if c.OOMMinBumpUp != nil && c.OOMMinBumpUp.Sign() < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("oomMinBumpUp"), c.OOMMinBumpUp.String(), "must not be negative"))
+	}
+
+	if c.History != nil && c.History.Address != "" {
+		allErrs = append(allErrs, validatePrometheusAddress(path.Child("history", "address"), c.History.Address)...)
+	}
+
+	return allErrs
 }
 
-// InjectClient injects the client.
-func (v *Validator) InjectClient(c client.Client) error {
-	v.client = c
+// validatePrometheusAddress checks that the address of the Prometheus API is
+// an http or https URL.
+func validatePrometheusAddress(path *field.Path, address string) field.ErrorList {
+	u, err := url.Parse(address)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, address, err.Error())}
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return field.ErrorList{field.Invalid(path, address, "must be an http or https URL")}
+	}
 	return nil
 }
 
-// InjectDecoder injects the decoder.
-func (v *Validator) InjectDecoder(d *admission.Decoder) error {
-	v.decoder = d
+// validateFeatureGates checks that the given feature gates are known to the
+// VPA version shipped with the operator.  The gates of an operand running an
+// overridden image are not checked, since its version is unknown.
+func validateFeatureGates(path *field.Path, gates map[string]bool, image string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for _, gate := range slices.Sorted(maps.Keys(gates)) {
+		switch {
+		case strings.ContainsAny(gate, ",= ") || gate == "":
+			allErrs = append(allErrs, field.Invalid(path.Key(gate), gate, "must not be empty or contain commas, equal signs or spaces"))
+		case image == "" && !KnownFeatureGates.Has(gate):
+			allErrs = append(allErrs, field.NotSupported(path.Key(gate), gate, sets.List(KnownFeatureGates)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateRecommenderName checks that the name of an alternative recommender
+// can be used in the names and labels of its deployment.
+func validateRecommenderName(path *field.Path, name string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for _, msg := range validation.IsDNS1123Label(name) {
+		allErrs = append(allErrs, field.Invalid(path, name, msg))
+	}
+	if name == DefaultRecommenderName {
+		allErrs = append(allErrs, field.Invalid(path, name, "is the name of the default recommender"))
+	}
+
+	return allErrs
+}
+
+// validateUpdater checks the fields of the updater config that cannot be
+// checked by the CRD schema.
+func validateUpdater(path *field.Path, c *autoscalingv1.UpdaterConfig) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateLogging(path.Child("logging"), c.Logging)...)
+
+	if c.EvictionRateLimit != nil && *c.EvictionRateLimit <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("evictionRateLimit"), *c.EvictionRateLimit,
+			"must be greater than zero, leave it unset to not limit the eviction rate"))
+	}
+	if c.EvictionRateBurst != nil && c.EvictionRateLimit == nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("evictionRateBurst"), "may only be set together with evictionRateLimit"))
+	}
+
+	durations := []struct {
+		name  string
+		value *metav1.Duration
+	}{
+		{"updaterInterval", c.UpdaterInterval},
+		{"evictAfterOOMThreshold", c.EvictAfterOOMThreshold},
+		{"inRecommendationBoundsEvictionLifetimeThreshold", c.InRecommendationBoundsEvictionLifetimeThreshold},
+	}
+	for _, d := range durations {
+		allErrs = append(allErrs, validatePositiveDuration(path.Child(d.name), d.value)...)
+	}
+
+	return allErrs
+}
+
+// validateAdmission checks the fields of the admission config that cannot be
+// checked by the CRD schema.
+func validateAdmission(path *field.Path, c *autoscalingv1.AdmissionConfig) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateLogging(path.Child("logging"), c.Logging)...)
+
+	if c.KubeAPIQPS != nil && *c.KubeAPIQPS <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("kubeAPIQPS"), *c.KubeAPIQPS, "must be greater than zero"))
+	}
+
+	for i, ns := range c.IgnoredNamespaces {
+		for _, msg := range validation.IsDNS1123Label(ns) {
+			allErrs = append(allErrs, field.Invalid(path.Child("ignoredNamespaces").Index(i), ns, msg))
+		}
+	}
+
+	return allErrs
+}
+
+// validateEnabled rejects enabling the updater or admission controller
+// explicitly when the spec disables it otherwise.
+func validateEnabled(path *field.Path, spec *autoscalingv1.VerticalPodAutoscalerControllerSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	recommendationOnly := spec.RecommendationOnly != nil && *spec.RecommendationOnly
+	if recommendationOnly && spec.Updater != nil && spec.Updater.Enabled != nil && *spec.Updater.Enabled {
+		allErrs = append(allErrs, field.Forbidden(path.Child("updater", "enabled"), "may not be true when recommendationOnly is true"))
+	}
+	if recommendationOnly && spec.Admission != nil && spec.Admission.Enabled != nil && *spec.Admission.Enabled {
+		allErrs = append(allErrs, field.Forbidden(path.Child("admission", "enabled"), "may not be true when recommendationOnly is true"))
+	}
+
+	return allErrs
+}
+
+// validateLogging checks that the vmodule of the given logging config, if
+// any, is a comma-separated list of pattern=N settings.
+func validateLogging(path *field.Path, l *autoscalingv1.LoggingConfig) field.ErrorList {
+	if l == nil || l.VModule == "" {
+		return nil
+	}
+
+	for _, setting := range strings.Split(l.VModule, ",") {
+		pattern, level, ok := strings.Cut(setting, "=")
+		if n, err := strconv.ParseUint(level, 10, 31); !ok || pattern == "" || strings.Contains(pattern, " ") || err != nil || n > 10 {
+			return field.ErrorList{field.Invalid(path.Child("vmodule"), l.VModule,
+				fmt.Sprintf("%q must be a file name pattern and a verbosity between 0 and 10, e.g. updater=4", setting))}
+		}
+	}
 	return nil
 }
+
+// validatePositiveDuration rejects a set duration that is zero or negative.
+func validatePositiveDuration(path *field.Path, d *metav1.Duration) field.ErrorList {
+	if d != nil && d.Duration <= 0 {
+		return field.ErrorList{field.Invalid(path, d.Duration.String(), "must be greater than zero")}
+	}
+	return nil
+}
+
+// validateOverrideArgs checks the override arguments, which replace or remove
+// the arguments set by the operator, or are added to them.
+func validateOverrideArgs(path *field.Path, args []string, operatorArgs []string) field.ErrorList {
+	var allErrs field.ErrorList
+
+	operatorFlags := sets.New[string]()
+	for _, arg := range util.ParseArgs(operatorArgs) {
+		operatorFlags.Insert(arg.Name)
+	}
+
+	for _, arg := range util.ParseArgs(args) {
+		argPath := path.Index(arg.Index)
+		switch {
+		case arg.Name == "":
+			allErrs = append(allErrs, field.Invalid(argPath, arg.Value, "must be a flag starting with a dash"))
+		case reservedArgs.Has(arg.Name):
+			allErrs = append(allErrs, field.Forbidden(argPath, "argument is managed by the operator"))
+		case arg.Remove && arg.HasValue:
+			allErrs = append(allErrs, field.Invalid(argPath, args[arg.Index], "an argument to remove must not have a value"))
+		case arg.Remove && !operatorFlags.Has(arg.Name):
+			allErrs = append(allErrs, field.Invalid(argPath, args[arg.Index], "only arguments set by the operator can be removed"))
+		}
+	}
+
+	return allErrs
+}
+
+// validatePodOverrides checks the pod metadata, priority class, env var, image
+// and image pull secret overrides of a deployment.
+func validatePodOverrides(path *field.Path, override *autoscalingv1.DeploymentOverride) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, metav1validation.ValidateLabels(override.Labels, path.Child("labels"))...)
+	for _, k := range sets.List(sets.KeySet(override.Labels).Intersection(reservedPodLabels)) {
+		allErrs = append(allErrs, field.Forbidden(path.Child("labels").Key(k), "label is set by the operator"))
+	}
+
+	allErrs = append(allErrs, apivalidation.ValidateAnnotations(override.Annotations, path.Child("annotations"))...)
+	for _, k := range sets.List(sets.KeySet(override.Annotations).Intersection(reservedPodAnnotations)) {
+		allErrs = append(allErrs, field.Forbidden(path.Child("annotations").Key(k), "annotation is set by the operator"))
+	}
+
+	if override.PriorityClassName != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(override.PriorityClassName) {
+			allErrs = append(allErrs, field.Invalid(path.Child("priorityClassName"), override.PriorityClassName, msg))
+		}
+	}
+
+	envPath := path.Child("container", "env")
+	for i, env := range override.Container.Env {
+		for _, msg := range validation.IsEnvVarName(env.Name) {
+			allErrs = append(allErrs, field.Invalid(envPath.Index(i).Child("name"), env.Name, msg))
+		}
+		if reservedEnvVars.Has(env.Name) {
+			allErrs = append(allErrs, field.Forbidden(envPath.Index(i).Child("name"), "env var is set by the operator"))
+		}
+	}
+
+	if image := override.Container.Image; image != strings.TrimSpace(image) {
+		allErrs = append(allErrs, field.Invalid(path.Child("container", "image"), image, "must not have leading or trailing whitespace"))
+	}
+
+	for i, secret := range override.ImagePullSecrets {
+		for _, msg := range validation.IsDNS1123Subdomain(secret.Name) {
+			allErrs = append(allErrs, field.Invalid(path.Child("imagePullSecrets").Index(i).Child("name"), secret.Name, msg))
+		}
+	}
+
+	return allErrs
+}
+
+// validateTolerations checks the given tolerations the same way the API
+// server would check them on the operand's pod template.
+func validateTolerations(path *field.Path, tolerations []corev1.Toleration) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, t := range tolerations {
+		idxPath := path.Index(i)
+
+		if t.Key != "" {
+			for _, msg := range validation.IsQualifiedName(t.Key) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("key"), t.Key, msg))
+			}
+		}
+
+		switch t.Operator {
+		case corev1.TolerationOpEqual, "":
+			if t.Key == "" {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("operator"), t.Operator, "operator must be Exists when key is empty"))
+			}
+			for _, msg := range validation.IsValidLabelValue(t.Value) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("value"), t.Value, msg))
+			}
+		case corev1.TolerationOpExists:
+			if t.Value != "" {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("value"), t.Value, "value must be empty when operator is Exists"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("operator"), t.Operator, sets.List(supportedTolerationOperators)))
+		}
+
+		if t.Effect != "" && !supportedTaintEffects.Has(string(t.Effect)) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("effect"), t.Effect, sets.List(supportedTaintEffects)))
+		}
+
+		if t.TolerationSeconds != nil && t.Effect != corev1.TaintEffectNoExecute {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("effect"), t.Effect, "effect must be NoExecute when tolerationSeconds is set"))
+		}
+	}
+
+	return allErrs
+}
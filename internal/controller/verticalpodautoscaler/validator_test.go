@@ -0,0 +1,566 @@
+package verticalpodautoscaler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	autoscalingv1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		label  string
+		mutate func(vpa *autoscalingv1.VerticalPodAutoscalerController)
+		// expected fields of the causes of the Invalid error, none if valid
+		fields []string
+	}{
+		{
+			label:  "valid",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {},
+		},
+		{
+			label: "non-default name",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Name = "other"
+			},
+			fields: []string{"metadata.name"},
+		},
+		{
+			label: "ignored namespaces and namespace selector",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.IgnoredNamespaces = []string{"team-a"}
+				vpa.Spec.NamespaceSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "vpa", Operator: metav1.LabelSelectorOpExists}},
+				}
+				vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{Enabled: ptr.To(false)}
+			},
+		},
+		{
+			label: "namespace selector with the updater enabled",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"vpa": "enabled"}}
+			},
+			fields: []string{"spec.namespaceSelector"},
+		},
+		{
+			label: "invalid ignored namespaces and namespace selector",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.IgnoredNamespaces = []string{"Team_A"}
+				vpa.Spec.NamespaceSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "vpa", Operator: metav1.LabelSelectorOpIn}},
+				}
+				vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{Enabled: ptr.To(false)}
+			},
+			fields: []string{
+				"spec.ignoredNamespaces[0]",
+				"spec.namespaceSelector.matchExpressions[0].values",
+			},
+		},
+		{
+			label: "image and pull secret overrides",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.DeploymentOverrides.Recommender.Container.Image = "mirror.example.com/vpa:patched"
+				vpa.Spec.DeploymentOverrides.Recommender.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "mirror-pull-secret"}}
+				vpa.Spec.DeploymentOverrides.Updater.Container.Image = " mirror.example.com/vpa:patched"
+				vpa.Spec.DeploymentOverrides.Updater.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "Not_Valid"}}
+			},
+			fields: []string{
+				"spec.deploymentOverrides.updater.container.image",
+				"spec.deploymentOverrides.updater.imagePullSecrets[0].name",
+			},
+		},
+		{
+			label: "override arg for the namespace set by the operator",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.DeploymentOverrides.Recommender.Container.Args = []string{"--ignored-vpa-object-namespaces=kube-system"}
+			},
+			fields: []string{"spec.deploymentOverrides.recommender.container.args[0]"},
+		},
+		{
+			label: "safety margin fraction above 1",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.SafetyMarginFraction = ptr.To(1.5)
+			},
+			fields: []string{"spec.safetyMarginFraction"},
+		},
+		{
+			label: "safety margin fraction of 1",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.SafetyMarginFraction = ptr.To(1.0)
+			},
+		},
+		{
+			label: "invalid recommender config",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				oomMinBumpUp := resource.MustParse("-1Mi")
+				vpa.Spec.Recommender = &autoscalingv1.RecommenderConfig{
+					CPUHistogramDecayHalfLife: &metav1.Duration{Duration: 12 * time.Hour},
+					RecommenderInterval:       &metav1.Duration{},
+					CheckpointsGCInterval:     &metav1.Duration{Duration: -time.Minute},
+					OOMMinBumpUp:              &oomMinBumpUp,
+				}
+			},
+			fields: []string{
+				"spec.recommender.recommenderInterval",
+				"spec.recommender.checkpointsGCInterval",
+				"spec.recommender.oomMinBumpUp",
+			},
+		},
+		{
+			label: "prometheus history",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Recommender = &autoscalingv1.RecommenderConfig{
+					History: &autoscalingv1.RecommenderHistory{Address: "http://prometheus.monitoring.svc:9090"},
+				}
+			},
+		},
+		{
+			label: "invalid prometheus address",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Recommender = &autoscalingv1.RecommenderConfig{
+					History: &autoscalingv1.RecommenderHistory{Address: "thanos-querier:9091"},
+				}
+				vpa.Spec.Recommenders = []autoscalingv1.NamedRecommender{
+					{Name: "frugal", Config: &autoscalingv1.RecommenderConfig{
+						History: &autoscalingv1.RecommenderHistory{Address: "https://"},
+					}},
+				}
+			},
+			fields: []string{
+				"spec.recommender.history.address",
+				"spec.recommenders[0].config.history.address",
+			},
+		},
+		{
+			label: "known feature gates",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{FeatureGates: map[string]bool{"InPlaceOrRecreate": true}}
+				vpa.Spec.Admission = &autoscalingv1.AdmissionConfig{FeatureGates: map[string]bool{"InPlaceOrRecreate": true}}
+			},
+		},
+		{
+			label: "unknown feature gate of an overridden image",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Recommender = &autoscalingv1.RecommenderConfig{FeatureGates: map[string]bool{"SomethingNew": true}}
+				vpa.Spec.DeploymentOverrides.Recommender.Container.Image = "mirror.example.com/vpa:next"
+			},
+		},
+		{
+			label: "unknown feature gates",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Recommender = &autoscalingv1.RecommenderConfig{FeatureGates: map[string]bool{"SomethingNew": true}}
+				vpa.Spec.Recommenders = []autoscalingv1.NamedRecommender{
+					{Name: "frugal", Config: &autoscalingv1.RecommenderConfig{FeatureGates: map[string]bool{"A=true,B": true}}},
+				}
+			},
+			fields: []string{
+				"spec.recommender.featureGates[SomethingNew]",
+				"spec.recommenders[0].config.featureGates[A=true,B]",
+			},
+		},
+		{
+			label: "invalid updater config",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{
+					EvictionTolerance:      ptr.To(0.5),
+					EvictionRateLimit:      ptr.To(0.0),
+					UpdaterInterval:        &metav1.Duration{},
+					EvictAfterOOMThreshold: &metav1.Duration{Duration: 10 * time.Minute},
+				}
+			},
+			fields: []string{
+				"spec.updater.evictionRateLimit",
+				"spec.updater.updaterInterval",
+			},
+		},
+		{
+			label: "logging",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{Logging: &autoscalingv1.LoggingConfig{
+					Verbosity: ptr.To(int32(4)),
+					VModule:   "updater=4,eviction*=6",
+					Format:    autoscalingv1.LogFormatJSON,
+				}}
+			},
+		},
+		{
+			label: "malformed vmodule",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Recommender = &autoscalingv1.RecommenderConfig{Logging: &autoscalingv1.LoggingConfig{VModule: "recommender"}}
+				vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{Logging: &autoscalingv1.LoggingConfig{VModule: "updater=4,=2"}}
+				vpa.Spec.Admission = &autoscalingv1.AdmissionConfig{Logging: &autoscalingv1.LoggingConfig{VModule: "server=high"}}
+			},
+			fields: []string{
+				"spec.recommender.logging.vmodule",
+				"spec.updater.logging.vmodule",
+				"spec.admission.logging.vmodule",
+			},
+		},
+		{
+			label: "debug session",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Annotations = map[string]string{DebugSessionAnnotation: "component=updater,verbosity=6,ttl=30m"}
+			},
+		},
+		{
+			label: "malformed debug session",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Annotations = map[string]string{DebugSessionAnnotation: "component=updater,verbosity=6,ttl=48h"}
+			},
+			fields: []string{"metadata.annotations[autoscaling.openshift.io/debug-session]"},
+		},
+		{
+			label: "debug session started in the future",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Annotations = map[string]string{
+					DebugSessionAnnotation:        "component=updater,verbosity=6,ttl=30m",
+					DebugSessionStartedAnnotation: time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+				}
+			},
+			fields: []string{"metadata.annotations[autoscaling.openshift.io/debug-session-started]"},
+		},
+		{
+			label: "eviction rate burst without a limit",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{EvictionRateBurst: ptr.To(int32(3))}
+			},
+			fields: []string{"spec.updater.evictionRateBurst"},
+		},
+		{
+			label: "override arg replacing a typed updater setting",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{EvictionTolerance: ptr.To(0.1)}
+				vpa.Spec.DeploymentOverrides.Updater.Container.Args = []string{"--eviction-tolerance=0.2"}
+			},
+		},
+		{
+			label: "override args replacing and removing args set by the operator",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.DeploymentOverrides.Recommender.Container.Args = []string{"--memory-saver=true", "--recommendation-margin-fraction", "0.3", "--logtostderr-"}
+				vpa.Spec.DeploymentOverrides.Updater.Container.Args = []string{"-v=4"}
+				vpa.Spec.DeploymentOverrides.Admission.Container.Args = []string{"--min-tls-version=VersionTLS13", "--kube-api-qps=6.0", "--kube-api-burst 11"}
+			},
+		},
+		{
+			label: "malformed override args",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.DeploymentOverrides.Recommender.Container.Args = []string{"--v=4", "4", "--memory-saver-", "--logtostderr-=true"}
+				vpa.Spec.DeploymentOverrides.Admission.Container.Args = []string{"--", "--leader-elect-resource-namespace=other"}
+			},
+			fields: []string{
+				"spec.deploymentOverrides.admission.container.args[0]",
+				"spec.deploymentOverrides.admission.container.args[1]",
+				"spec.deploymentOverrides.recommender.container.args[1]",
+				"spec.deploymentOverrides.recommender.container.args[2]",
+				"spec.deploymentOverrides.recommender.container.args[3]",
+			},
+		},
+		{
+			label: "valid admission config",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Admission = &autoscalingv1.AdmissionConfig{
+					WebhookTimeoutSeconds: ptr.To(int32(5)),
+					FailurePolicy:         ptr.To(admissionregistrationv1.Fail),
+					KubeAPIQPS:            ptr.To(10.0),
+					KubeAPIBurst:          ptr.To(int32(20)),
+				}
+			},
+		},
+		{
+			label: "invalid admission config",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Admission = &autoscalingv1.AdmissionConfig{
+					FailurePolicy:     ptr.To(admissionregistrationv1.Fail),
+					KubeAPIQPS:        ptr.To(0.0),
+					IgnoredNamespaces: []string{"kube-system", "Not_A_Namespace"},
+				}
+			},
+			fields: []string{
+				"spec.admission.kubeAPIQPS",
+				"spec.admission.ignoredNamespaces[1]",
+			},
+		},
+		{
+			label: "admission controller without the updater",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{Enabled: ptr.To(false)}
+				vpa.Spec.Admission = &autoscalingv1.AdmissionConfig{Enabled: ptr.To(true)}
+			},
+		},
+		{
+			label: "operands enabled in recommendation-only mode",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.RecommendationOnly = ptr.To(true)
+				vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{Enabled: ptr.To(true)}
+				vpa.Spec.Admission = &autoscalingv1.AdmissionConfig{Enabled: ptr.To(true)}
+			},
+			fields: []string{
+				"spec.updater.enabled",
+				"spec.admission.enabled",
+			},
+		},
+		{
+			label: "valid pod overrides",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.DeploymentOverrides.Recommender.Labels = map[string]string{"sidecar.istio.io/inject": "false"}
+				vpa.Spec.DeploymentOverrides.Recommender.Annotations = map[string]string{"example.com/cost-center": "platform team"}
+				vpa.Spec.DeploymentOverrides.Recommender.PriorityClassName = "openshift-user-critical"
+				vpa.Spec.DeploymentOverrides.Recommender.Container.Env = []corev1.EnvVar{{Name: "GOMEMLIMIT", Value: "200MiB"}}
+			},
+		},
+		{
+			label: "invalid pod overrides",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.DeploymentOverrides.Updater.Labels = map[string]string{"app": "other", "bad key!": "x"}
+				vpa.Spec.DeploymentOverrides.Updater.Annotations = map[string]string{"release.openshift.io/version": "v1"}
+				vpa.Spec.DeploymentOverrides.Updater.PriorityClassName = "Not_Valid"
+				vpa.Spec.DeploymentOverrides.Updater.Container.Env = []corev1.EnvVar{{Name: "NAMESPACE", Value: "x"}, {Name: "1BAD"}}
+			},
+			fields: []string{
+				"spec.deploymentOverrides.updater.labels",
+				"spec.deploymentOverrides.updater.labels[app]",
+				"spec.deploymentOverrides.updater.annotations[release.openshift.io/version]",
+				"spec.deploymentOverrides.updater.priorityClassName",
+				"spec.deploymentOverrides.updater.container.env[0].name",
+				"spec.deploymentOverrides.updater.container.env[1].name",
+			},
+		},
+		{
+			label: "valid alternative recommenders",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Recommenders = []autoscalingv1.NamedRecommender{
+					{Name: "db", Config: &autoscalingv1.RecommenderConfig{TargetCPUPercentile: ptr.To(0.99)}},
+					{Name: "web", DeploymentOverride: autoscalingv1.DeploymentOverride{Replicas: ptr.To(int32(2))}},
+				}
+			},
+		},
+		{
+			label: "invalid alternative recommenders",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.Recommenders = []autoscalingv1.NamedRecommender{
+					{Name: "default"},
+					{Name: "Not_Valid"},
+					{
+						Name:   "db",
+						Config: &autoscalingv1.RecommenderConfig{RecommenderInterval: &metav1.Duration{}},
+						DeploymentOverride: autoscalingv1.DeploymentOverride{
+							Container: autoscalingv1.ContainerOverride{Args: []string{"--recommender-name=other"}},
+						},
+					},
+				}
+			},
+			fields: []string{
+				"spec.recommenders[0].name",
+				"spec.recommenders[1].name",
+				"spec.recommenders[2].config.recommenderInterval",
+				"spec.recommenders[2].deploymentOverride.container.args[0]",
+			},
+		},
+		{
+			label: "valid tolerations",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.DeploymentOverrides.Updater.Tolerations = []corev1.Toleration{
+					{Key: "example.com/dedicated", Operator: corev1.TolerationOpEqual, Value: "vpa", Effect: corev1.TaintEffectNoSchedule},
+					{Operator: corev1.TolerationOpExists},
+					{Key: "node.kubernetes.io/unreachable", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute, TolerationSeconds: ptr.To(int64(300))},
+				}
+			},
+		},
+		{
+			label: "malformed tolerations",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.DeploymentOverrides.Admission.Tolerations = []corev1.Toleration{
+					{Key: "bad key!", Operator: corev1.TolerationOpExists},
+					{Operator: corev1.TolerationOpEqual, Value: "vpa"},
+					{Key: "dedicated", Operator: corev1.TolerationOpExists, Value: "vpa"},
+					{Key: "dedicated", Operator: "Matches"},
+					{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: "NoRun"},
+					{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule, TolerationSeconds: ptr.To(int64(300))},
+				}
+			},
+			fields: []string{
+				"spec.deploymentOverrides.admission.tolerations[0].key",
+				"spec.deploymentOverrides.admission.tolerations[1].operator",
+				"spec.deploymentOverrides.admission.tolerations[2].value",
+				"spec.deploymentOverrides.admission.tolerations[3].operator",
+				"spec.deploymentOverrides.admission.tolerations[4].effect",
+				"spec.deploymentOverrides.admission.tolerations[5].effect",
+			},
+		},
+	}
+
+	v := &Validator{Config: TestReconcilerConfig}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			vpa := NewVerticalPodAutoscaler()
+			tc.mutate(vpa)
+
+			_, createErr := v.ValidateCreate(context.TODO(), vpa)
+
+			if len(tc.fields) == 0 {
+				assert.NoError(t, createErr)
+				return
+			}
+
+			assert.True(t, errors.IsInvalid(createErr), "expected an Invalid error, got %v", createErr)
+			var fields []string
+			for _, cause := range createErr.(*errors.StatusError).ErrStatus.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			assert.Equal(t, tc.fields, fields)
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	// invalid returns a copy of the given VerticalPodAutoscalerController
+	// whose spec no longer validates, e.g. after a validation was tightened.
+	invalid := func(vpa *autoscalingv1.VerticalPodAutoscalerController) *autoscalingv1.VerticalPodAutoscalerController {
+		vpa = vpa.DeepCopy()
+		vpa.Spec.SafetyMarginFraction = ptr.To(float64(2))
+		return vpa
+	}
+
+	testCases := []struct {
+		label  string
+		old    *autoscalingv1.VerticalPodAutoscalerController
+		mutate func(vpa *autoscalingv1.VerticalPodAutoscalerController)
+		// expected fields of the causes of the Invalid error, none if valid
+		fields []string
+	}{
+		{
+			label: "label edit of an invalid resource",
+			old:   invalid(NewVerticalPodAutoscaler()),
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Labels = map[string]string{"team": "a"}
+			},
+		},
+		{
+			label: "spec edit of an invalid resource",
+			old:   invalid(NewVerticalPodAutoscaler()),
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.MinReplicas = ptr.To[int64](2)
+			},
+			fields: []string{"spec.safetyMarginFraction"},
+		},
+		{
+			label: "spec fixed",
+			old:   invalid(NewVerticalPodAutoscaler()),
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.SafetyMarginFraction = nil
+			},
+		},
+		{
+			label: "deletion of a resource whose spec no longer validates",
+			old: func() *autoscalingv1.VerticalPodAutoscalerController {
+				vpa := invalid(NewVerticalPodAutoscaler())
+				vpa.Finalizers = []string{TeardownFinalizer}
+				vpa.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				return vpa
+			}(),
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Finalizers = nil
+				vpa.Spec.MinReplicas = ptr.To[int64](2)
+			},
+		},
+		{
+			label: "malformed debug session added",
+			old:   invalid(NewVerticalPodAutoscaler()),
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Annotations = map[string]string{DebugSessionAnnotation: "component=updater,verbosity=6,ttl=48h"}
+			},
+			fields: []string{"metadata.annotations[autoscaling.openshift.io/debug-session]"},
+		},
+		{
+			label: "malformed debug session removed",
+			old: func() *autoscalingv1.VerticalPodAutoscalerController {
+				vpa := NewVerticalPodAutoscaler()
+				vpa.Annotations = map[string]string{DebugSessionAnnotation: "component=updater,verbosity=6,ttl=48h"}
+				return vpa
+			}(),
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				delete(vpa.Annotations, DebugSessionAnnotation)
+			},
+		},
+		{
+			label: "debug session started",
+			old: func() *autoscalingv1.VerticalPodAutoscalerController {
+				vpa := NewVerticalPodAutoscaler()
+				vpa.Annotations = map[string]string{DebugSessionAnnotation: "component=updater,verbosity=6,ttl=30m"}
+				return vpa
+			}(),
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Annotations[DebugSessionStartedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+			},
+		},
+		{
+			label: "debug session start moved to the future",
+			old: func() *autoscalingv1.VerticalPodAutoscalerController {
+				vpa := NewVerticalPodAutoscaler()
+				vpa.Annotations = map[string]string{
+					DebugSessionAnnotation:        "component=updater,verbosity=6,ttl=30m",
+					DebugSessionStartedAnnotation: time.Now().UTC().Format(time.RFC3339),
+				}
+				return vpa
+			}(),
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Annotations[DebugSessionStartedAnnotation] = time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+			},
+			fields: []string{"metadata.annotations[autoscaling.openshift.io/debug-session-started]"},
+		},
+	}
+
+	v := &Validator{Config: TestReconcilerConfig}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			vpa := tc.old.DeepCopy()
+			tc.mutate(vpa)
+
+			_, err := v.ValidateUpdate(context.TODO(), tc.old, vpa)
+			if len(tc.fields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, errors.IsInvalid(err), "expected an Invalid error, got %v", err)
+			var fields []string
+			for _, cause := range err.(*errors.StatusError).ErrStatus.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			assert.Equal(t, tc.fields, fields)
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	r := newFakeReconciler(NewVerticalPodAutoscaler())
+	d := &Defaulter{}
+
+	t.Run("empty spec gets the operator defaults", func(t *testing.T) {
+		vpa := &autoscalingv1.VerticalPodAutoscalerController{}
+		vpa.Name = r.Config.Name
+		vpa.Namespace = r.Config.Namespace
+
+		assert.NoError(t, d.Default(context.TODO(), vpa))
+		assert.Equal(t, r.DefaultVPAController().Spec, vpa.Spec)
+	})
+
+	t.Run("set fields are kept", func(t *testing.T) {
+		vpa := NewVerticalPodAutoscaler()
+		expected := vpa.Spec.DeepCopy()
+		expected.MinReplicas = ptr.To(DefaultMinReplicas)
+
+		assert.NoError(t, d.Default(context.TODO(), vpa))
+		assert.Equal(t, *expected, vpa.Spec)
+	})
+}
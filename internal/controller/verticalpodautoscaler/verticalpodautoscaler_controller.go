@@ -19,12 +19,19 @@ package verticalpodautoscaler
 import (
 	"context"
 	"fmt"
+	"maps"
+	"net/url"
 	"reflect"
+	"slices"
+	"strings"
 	"time"
 
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -32,8 +39,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	admissionregistrationv1ac "k8s.io/client-go/applyconfigurations/admissionregistration/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/tools/reference"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
 	"k8s.io/utils/ptr"
 
@@ -41,7 +51,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -62,6 +72,8 @@ const (
 	DefaultVPAControllerAnnotation = "autoscaling.openshift.io/default-vpa-controller-created"
 	// WebhookServiceName The hard-coded name of the VPA webhook
 	WebhookServiceName = "vpa-webhook"
+	// WebhookConfigName The hard-coded name of the webhook configuration registered by the VPA admission controller
+	WebhookConfigName = "vpa-webhook-config"
 	// WebhookCertSecretName The hard-coded name of the secret containing the VPA webhook's TLS cert
 	WebhookCertSecretName     = "vpa-tls-certs"
 	webhookCertAnnotationName = "service.beta.openshift.io/serving-cert-secret-name"
@@ -71,6 +83,12 @@ const (
 
 	// AdmissionControllerAppName The hard-coded name of the VPA admission controller
 	AdmissionControllerAppName = "vpa-admission-controller"
+	// RecommenderCommand The command of the VPA recommender
+	RecommenderCommand = "recommender"
+	// UpdaterCommand The command of the VPA updater
+	UpdaterCommand = "updater"
+	// AdmissionControllerCommand The command of the VPA admission controller
+	AdmissionControllerCommand = "admission-controller"
 	// DefaultSafetyMarginFraction Fraction of usage added as the safety margin to the recommended request. This default
 	// matches the upstream default
 	DefaultSafetyMarginFraction = float64(0.15)
@@ -114,49 +132,115 @@ type ControllerParams struct {
 	NameMethod           func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController) types.NamespacedName
 	AppName              string
 	ServiceAccount       string
+	LeaseName            string
 	PriorityClassName    string
 	GetArgs              func(vpa *autoscalingv1.VerticalPodAutoscalerController, cfg *Config) []string
 	EnabledMethod        func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController) bool
 	PodSpecMethod        func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) *corev1.PodSpec
 	ResourceRequirements corev1.ResourceRequirements
+	StatusMethod         func(status *autoscalingv1.VerticalPodAutoscalerControllerStatus) *autoscalingv1.OperandStatus
+	OverrideMethod       func(vpa *autoscalingv1.VerticalPodAutoscalerController) *autoscalingv1.DeploymentOverride
 }
 
 var controllerParams = [...]ControllerParams{
 	{
-		"recommender",
+		RecommenderCommand,
 		(*VerticalPodAutoscalerControllerReconciler).RecommenderName,
 		"vpa-recommender",
 		"vpa-recommender",
+		RecommenderLeaseName,
 		"system-cluster-critical",
 		RecommenderArgs,
 		(*VerticalPodAutoscalerControllerReconciler).RecommenderEnabled,
 		(*VerticalPodAutoscalerControllerReconciler).RecommenderControllerPodSpec,
 		RecommenderResourceRequirements,
+		RecommenderStatus,
+		RecommenderOverride,
 	},
 	{
-		"updater",
+		UpdaterCommand,
 		(*VerticalPodAutoscalerControllerReconciler).UpdaterName,
 		"vpa-updater",
 		"vpa-updater",
+		UpdaterLeaseName,
 		"system-cluster-critical",
 		UpdaterArgs,
 		(*VerticalPodAutoscalerControllerReconciler).UpdaterEnabled,
 		(*VerticalPodAutoscalerControllerReconciler).UpdaterControllerPodSpec,
 		UpdaterResourceRequirements,
+		UpdaterStatus,
+		UpdaterOverride,
 	},
 	{
-		"admission-controller",
+		AdmissionControllerCommand,
 		(*VerticalPodAutoscalerControllerReconciler).AdmissionPluginName,
 		AdmissionControllerAppName,
 		"vpa-admission-controller",
+		"",
 		"system-cluster-critical",
 		AdmissionPluginArgs,
 		(*VerticalPodAutoscalerControllerReconciler).AdmissionPluginEnabled,
 		(*VerticalPodAutoscalerControllerReconciler).AdmissionControllerPodSpec,
 		AdmissionResourceRequirements,
+		AdmissionPluginStatus,
+		AdmissionPluginOverride,
 	},
 }
 
+// OperandParams returns the parameters of every operand of the given
+// VerticalPodAutoscalerController: the fixed operands followed by one
+// recommender per entry of spec.recommenders.
+func OperandParams(vpa *autoscalingv1.VerticalPodAutoscalerController) []ControllerParams {
+	params := controllerParams[:]
+	for _, rec := range vpa.Spec.Recommenders {
+		params = append(params, NamedRecommenderParams(rec.Name))
+	}
+	return params
+}
+
+// NamedRecommenderParams returns the parameters of the alternative
+// recommender with the given name.
+func NamedRecommenderParams(name string) ControllerParams {
+	return ControllerParams{
+		Command: RecommenderCommand,
+		NameMethod: func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController) types.NamespacedName {
+			return types.NamespacedName{
+				Name:      fmt.Sprintf("vpa-recommender-%s-%s", name, vpa.Name),
+				Namespace: r.Config.Namespace,
+			}
+		},
+		AppName:           fmt.Sprintf("vpa-recommender-%s", name),
+		ServiceAccount:    "vpa-recommender",
+		LeaseName:         NamedRecommenderLeaseName(name),
+		PriorityClassName: "system-cluster-critical",
+		GetArgs: func(vpa *autoscalingv1.VerticalPodAutoscalerController, cfg *Config) []string {
+			return NamedRecommenderArgs(vpa, namedRecommender(vpa, name), cfg)
+		},
+		EnabledMethod: (*VerticalPodAutoscalerControllerReconciler).RecommenderEnabled,
+		PodSpecMethod: func(r *VerticalPodAutoscalerControllerReconciler, vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) *corev1.PodSpec {
+			return r.recommenderPodSpec(vpa, params, namedRecommender(vpa, name).Config)
+		},
+		ResourceRequirements: RecommenderResourceRequirements,
+		StatusMethod: func(status *autoscalingv1.VerticalPodAutoscalerControllerStatus) *autoscalingv1.OperandStatus {
+			return NamedRecommenderStatus(status, name)
+		},
+		OverrideMethod: func(vpa *autoscalingv1.VerticalPodAutoscalerController) *autoscalingv1.DeploymentOverride {
+			return &namedRecommender(vpa, name).DeploymentOverride
+		},
+	}
+}
+
+// namedRecommender returns the alternative recommender with the given name,
+// or an empty one if the VerticalPodAutoscalerController has none.
+func namedRecommender(vpa *autoscalingv1.VerticalPodAutoscalerController, name string) *autoscalingv1.NamedRecommender {
+	for i := range vpa.Spec.Recommenders {
+		if vpa.Spec.Recommenders[i].Name == name {
+			return &vpa.Spec.Recommenders[i]
+		}
+	}
+	return &autoscalingv1.NamedRecommender{Name: name}
+}
+
 // Config represents the configuration for a reconciler instance.
 type Config struct {
 	// The release version assigned to the operator config.
@@ -169,7 +253,9 @@ type Config struct {
 	Image string
 	// The log verbosity level for the vertical-pod-autoscaler.
 	Verbosity int
-	// Additional arguments passed to the vertical-pod-autoscaler.
+	// Additional arguments passed to the vertical-pod-autoscaler, split like
+	// shell words.  Arguments prefixed with the command of an operand and a
+	// colon, e.g. "recommender:--v=4", are only passed to that operand.
 	ExtraArgs string
 	// TLSProfileSpec is the TLS profile to use for the admission webhook server. nil value indicates that default TLS config should be used
 	TLSProfileSpec *configv1.TLSProfileSpec
@@ -177,6 +263,9 @@ type Config struct {
 	// control plane (HCP/Hosted Control Plane topology). When true, VPA
 	// components should schedule on worker nodes instead of master nodes.
 	IsExternalControlPlane bool
+	// Namespaces are the names of every namespace of the cluster, in sorted
+	// order, or nil until they are first listed.
+	Namespaces []string
 }
 
 // VerticalPodAutoscalerControllerReconciler reconciles a VerticalPodAutoscalerController object
@@ -192,16 +281,22 @@ type VerticalPodAutoscalerControllerReconciler struct {
 // +kubebuilder:rbac:groups=autoscaling.openshift.io,resources=verticalpodautoscalercontrollers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling.openshift.io,resources=verticalpodautoscalercontrollers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=autoscaling.openshift.io,resources=verticalpodautoscalercontrollers/finalizers,verbs=update
-// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch;get;list;watch;update
-// +kubebuilder:rbac:groups="",resources=configmaps;services,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=list;watch
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,resourceNames=vpa-webhook-config,verbs=get;patch;delete
 // +kubebuilder:rbac:groups=config.openshift.io,resources=apiservers,verbs=get;list;watch
 // +kubebuilder:rbac:groups=config.openshift.io,resources=infrastructures,verbs=get
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=list;get;patch;watch
+// +kubebuilder:rbac:groups="",namespace=openshift-vertical-pod-autoscaler,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,namespace=openshift-vertical-pod-autoscaler,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 
-func (r *VerticalPodAutoscalerControllerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *VerticalPodAutoscalerControllerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	reqLogger := r.Log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
 	reqLogger.Info("Reconciling VerticalPodAutoscalerController")
 
@@ -210,7 +305,7 @@ func (r *VerticalPodAutoscalerControllerReconciler) Reconcile(ctx context.Contex
 
 	// Fetch the VerticalPodAutoscalerController instance
 	vpa := &autoscalingv1.VerticalPodAutoscalerController{}
-	err := r.Get(context.TODO(), req.NamespacedName, vpa)
+	err = r.Get(context.TODO(), req.NamespacedName, vpa)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after
@@ -231,173 +326,195 @@ func (r *VerticalPodAutoscalerControllerReconciler) Reconcile(ctx context.Contex
 	// generated for these cluster scoped objects out of the default namespace.
 	vpaRef := r.objectReference(vpa)
 
-	for _, params := range controllerParams {
-		deployment := &appsv1.Deployment{}
-		err := r.Get(context.TODO(), params.NameMethod(r, vpa), deployment)
-		if err != nil && !errors.IsNotFound(err) {
-			errMsg := fmt.Sprintf("Error getting vertical-pod-autoscaler deployment: %v", err)
-			r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedGetDeployment", "GetDeployment", "%s", errMsg)
-			klog.Error(errMsg)
-
-			return reconcile.Result{}, err
-		}
-
-		if errors.IsNotFound(err) {
-			if err := r.CreateAutoscaler(vpa, params); err != nil {
-				errMsg := fmt.Sprintf("Error creating VerticalPodAutoscalerController deployment: %v", err)
-				r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedCreate", "Create", "%s", errMsg)
-				klog.Error(errMsg)
-
-				return reconcile.Result{}, err
+	// A VerticalPodAutoscalerController being deleted only tears down the cluster-scoped
+	// objects of its operands, and leaves the rest to the garbage collector.
+	if !vpa.DeletionTimestamp.IsZero() {
+		return r.teardown(ctx, vpa, vpaRef)
+	}
+
+	// Record any failures so they can be reported in the status, which is
+	// synced however this pass ends.  Every resource is attempted even if
+	// another one failed, so one bad resource does not hold up the rest.
+	var failures reconcileFailures
+	defer func() {
+		if statusErr := r.SyncStatus(ctx, vpa, failures); statusErr != nil {
+			klog.Errorf("Error updating VerticalPodAutoscalerController status: %v", statusErr)
+			if err == nil {
+				err = statusErr
 			}
-
-			msg := fmt.Sprintf("Created VerticalPodAutoscalerController deployment: %s", params.NameMethod(r, vpa))
-			r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeNormal, "SuccessfulCreate", "Create", "%s", msg)
-			klog.Info(msg)
-			continue
 		}
-		if updated, err := r.UpdateAutoscaler(vpa, params); err != nil {
-			errMsg := fmt.Sprintf("Error updating vertical-pod-autoscaler deployment: %v", err)
-			r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedUpdate", "Update", "%s", errMsg)
-			klog.Error(errMsg)
+	}()
 
-			return reconcile.Result{}, err
-		} else if updated {
-			msg := fmt.Sprintf("Updated VerticalPodAutoscalerController deployment: %s", params.NameMethod(r, vpa))
-			r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeNormal, "SuccessfulUpdate", "Update", "%s", msg)
-			klog.Info(msg)
-		}
+	// Fetch the namespaces of the cluster, among which the platform namespaces are
+	// ignored.  The ones listed last are used if this fails.
+	if err := r.syncNamespaces(ctx); err != nil {
+		failures.add(reconcileFailure{
+			Kind: "VerticalPodAutoscalerController", Name: vpa.Name, Reason: "FailedList",
+			Message: fmt.Sprintf("Error listing namespaces: %v", err),
+		})
 	}
 
-	whnn := types.NamespacedName{
-		Name:      WebhookServiceName,
-		Namespace: r.Config.Namespace,
+	if err := r.ensureTeardownFinalizer(ctx, vpa); err != nil {
+		failures.add(reconcileFailure{
+			Kind: "VerticalPodAutoscalerController", Name: vpa.Name, Reason: "FailedUpdate",
+			Message: fmt.Sprintf("Error adding the finalizer of VerticalPodAutoscalerController %s: %v", vpa.Name, err),
+		})
 	}
 
-	service := &corev1.Service{}
-	err = r.Get(context.TODO(), whnn, service)
-	if err != nil && !errors.IsNotFound(err) {
-		errMsg := fmt.Sprintf("Error getting vertical-pod-autoscaler webhook service %v: %v", WebhookServiceName, err)
-		r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedGetService", "GetService", "%s", errMsg)
-		klog.Error(errMsg)
-
-		return reconcile.Result{}, err
+	// Start or end the debug session, requeueing to end it once it expires
+	debugSessionRemaining, err := r.syncDebugSession(ctx, vpa, vpaRef)
+	if err != nil {
+		failures.add(reconcileFailure{
+			Kind: "VerticalPodAutoscalerController", Name: vpa.Name, Reason: "FailedUpdate",
+			Message: fmt.Sprintf("Error updating the debug session of VerticalPodAutoscalerController %s: %v", vpa.Name, err),
+		})
 	}
 
-	if errors.IsNotFound(err) {
-		if err := r.CreateWebhookService(vpa); err != nil {
-			errMsg := fmt.Sprintf("Error creating VerticalPodAutoscalerController service: %v", err)
-			r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedCreate", "Create", "%s", errMsg)
-			klog.Error(errMsg)
-
-			return reconcile.Result{}, err
-		}
-
-		msg := fmt.Sprintf("Created VerticalPodAutoscalerController service: %s", WebhookServiceName)
-		r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeNormal, "SuccessfulCreate", "Create", "%s", msg)
-		klog.Info(msg)
-	} else {
-		if updated, err := r.UpdateWebhookService(vpa); err != nil {
-			errMsg := fmt.Sprintf("Error updating vertical-pod-autoscaler webhook service: %v", err)
-			r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedUpdate", "Update", "%s", errMsg)
-			klog.Error(errMsg)
-
-			return reconcile.Result{}, err
-		} else if updated {
-			msg := fmt.Sprintf("Updated VerticalPodAutoscalerController service: %s", WebhookServiceName)
-			r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeNormal, "SuccessfulUpdate", "Update", "%s", msg)
-			klog.Info(msg)
+	// The operands are granted access to their leases before they start, so
+	// they can elect a leader right away.  The Roles that are no longer
+	// expected are pruned below.
+	for _, role := range r.LeaderLockingRoles(vpa) {
+		result, err := r.applyObject(ctx, vpa, &role)
+		if err != nil {
+			failures.add(reconcileFailure{
+				Kind: "Role", Name: role.Name, Reason: "FailedApply",
+				Message: fmt.Sprintf("Error applying VerticalPodAutoscalerController role %s: %v", role.Name, err),
+			})
+			continue
 		}
+		r.recordApply(vpaRef, "Role", role.Name, result)
 	}
-
-	cmnn := types.NamespacedName{
-		Name:      CACertConfigMapName,
-		Namespace: r.Config.Namespace,
+	for _, binding := range r.LeaderLockingRoleBindings(vpa) {
+		result, err := r.applyObject(ctx, vpa, &binding)
+		if err != nil {
+			failures.add(reconcileFailure{
+				Kind: "RoleBinding", Name: binding.Name, Reason: "FailedApply",
+				Message: fmt.Sprintf("Error applying VerticalPodAutoscalerController rolebinding %s: %v", binding.Name, err),
+			})
+			continue
+		}
+		r.recordApply(vpaRef, "RoleBinding", binding.Name, result)
 	}
-	cm := &corev1.ConfigMap{}
-	err = r.Get(context.TODO(), cmnn, cm)
-	if err != nil && !errors.IsNotFound(err) {
-		errMsg := fmt.Sprintf("Error getting vertical-pod-autoscaler CA ConfigMap %v: %v", CACertConfigMapName, err)
-		r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedGetConfigMap", "GetConfigMap", "%s", errMsg)
-		klog.Error(errMsg)
 
-		return reconcile.Result{}, err
+	for _, params := range OperandParams(vpa) {
+		// The deployments of disabled operands are pruned below.
+		if !params.EnabledMethod(r, vpa) {
+			continue
+		}
+		// Until the namespaces are listed, the operands would not ignore the
+		// platform namespaces with a prefix, so they are left as they are.
+		if r.Config.Namespaces == nil {
+			continue
+		}
+		name := params.NameMethod(r, vpa).Name
+		result, err := r.ApplyAutoscaler(ctx, vpa, params)
+		if err != nil {
+			failures.add(reconcileFailure{
+				Operand: params.AppName, Kind: "Deployment", Name: name, Reason: "FailedApply",
+				Message: fmt.Sprintf("Error applying vertical-pod-autoscaler deployment %s: %v", name, err),
+			})
+			continue
+		}
+		r.recordApply(vpaRef, "Deployment", name, result)
+	}
+
+	// A disabled admission controller leaves its webhook configuration behind, which
+	// would send pods to a webhook that is gone.
+	if !r.AdmissionPluginEnabled(vpa) {
+		if deleted, err := r.DeleteWebhookConfig(ctx); err != nil {
+			failures.add(reconcileFailure{
+				Operand: AdmissionControllerAppName, Kind: "MutatingWebhookConfiguration", Name: WebhookConfigName, Reason: "FailedDelete",
+				Message: fmt.Sprintf("Error deleting vertical-pod-autoscaler webhook configuration %s: %v", WebhookConfigName, err),
+			})
+		} else if deleted {
+			msg := fmt.Sprintf("Deleted VerticalPodAutoscalerController webhook configuration: %s", WebhookConfigName)
+			r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeNormal, "SuccessfulDelete", "Delete", "%s", msg)
+			klog.Info(msg)
+		}
 	}
 
-	if errors.IsNotFound(err) {
-		if err := r.CreateCAConfigMap(vpa); err != nil {
-			errMsg := fmt.Sprintf("Error creating VerticalPodAutoscalerController ConfigMap: %v", err)
-			r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedCreate", "Create", "%s", errMsg)
-			klog.Error(errMsg)
-
-			return reconcile.Result{}, err
+	// The webhook service is only used by an enabled admission controller, and is
+	// pruned otherwise.
+	if r.AdmissionPluginEnabled(vpa) {
+		if result, err := r.ApplyWebhookService(ctx, vpa); err != nil {
+			failures.add(reconcileFailure{
+				Operand: AdmissionControllerAppName, Kind: "Service", Name: WebhookServiceName, Reason: "FailedApply",
+				Message: fmt.Sprintf("Error applying vertical-pod-autoscaler webhook service %s: %v", WebhookServiceName, err),
+			})
+		} else {
+			r.recordApply(vpaRef, "Service", WebhookServiceName, result)
 		}
+	}
 
-		msg := fmt.Sprintf("Created VerticalPodAutoscalerController ConfigMap: %s", CACertConfigMapName)
-		r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeNormal, "SuccessfulCreate", "Create", "%s", msg)
-		klog.Info(msg)
-	} else {
-		if updated, err := r.UpdateCAConfigMap(vpa); err != nil {
-			errMsg := fmt.Sprintf("Error updating vertical-pod-autoscaler CA ConfigMap: %v", err)
-			r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedUpdate", "Update", "%s", errMsg)
-			klog.Error(errMsg)
-
-			return reconcile.Result{}, err
+	// The admission controller registers its webhook itself, once it is running, and
+	// only knows the namespaces to ignore by name, so the namespace selector and the
+	// match condition excluding the platform namespaces are set here.
+	if r.AdmissionPluginEnabled(vpa) {
+		if updated, err := r.UpdateWebhookNamespaceScope(ctx, vpa); err != nil {
+			failures.add(reconcileFailure{
+				Operand: AdmissionControllerAppName, Kind: "MutatingWebhookConfiguration", Name: WebhookConfigName, Reason: "FailedUpdate",
+				Message: fmt.Sprintf("Error updating vertical-pod-autoscaler webhook configuration %s: %v", WebhookConfigName, err),
+			})
 		} else if updated {
-			msg := fmt.Sprintf("Updated VerticalPodAutoscalerController ConfigMap: %s", CACertConfigMapName)
+			msg := fmt.Sprintf("Updated VerticalPodAutoscalerController webhook configuration: %s", WebhookConfigName)
 			r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeNormal, "SuccessfulUpdate", "Update", "%s", msg)
 			klog.Info(msg)
 		}
 	}
 
+	if result, err := r.ApplyCAConfigMap(ctx, vpa); err != nil {
+		failures.add(reconcileFailure{
+			Operand: AdmissionControllerAppName, Kind: "ConfigMap", Name: CACertConfigMapName, Reason: "FailedApply",
+			Message: fmt.Sprintf("Error applying vertical-pod-autoscaler CA ConfigMap %s: %v", CACertConfigMapName, err),
+		})
+	} else {
+		r.recordApply(vpaRef, "ConfigMap", CACertConfigMapName, result)
+	}
+
 	for _, policy := range r.NetworkPolicies(vpa) {
-		oldpolicy := &networkingv1.NetworkPolicy{}
-		err = r.Get(context.TODO(), types.NamespacedName{Name: policy.Name, Namespace: r.Config.Namespace}, oldpolicy)
-		if err != nil && !errors.IsNotFound(err) {
-			errMsg := fmt.Sprintf("Error getting VerticalPodAutoscalerController networkpolicy %v: %v", policy.Name, err)
-			r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedGetNetworkPolicy", "GetNetworkPolicy", "%s", errMsg)
-			klog.Error(errMsg)
-
-			return reconcile.Result{}, err
+		result, err := r.applyObject(ctx, vpa, &policy)
+		if err != nil {
+			failures.add(reconcileFailure{
+				Kind: "NetworkPolicy", Name: policy.Name, Reason: "FailedApply",
+				Message: fmt.Sprintf("Error applying VerticalPodAutoscalerController networkpolicy %s: %v", policy.Name, err),
+			})
+			continue
 		}
+		r.recordApply(vpaRef, "NetworkPolicy", policy.Name, result)
+	}
 
-		if errors.IsNotFound(err) {
-
-			// Set VerticalPodAutoscalerController instance as the owner and controller.
-			if err := controllerutil.SetControllerReference(vpa, &policy, r.Scheme); err != nil {
-				return reconcile.Result{}, err
-			}
-
-			if err := r.Create(context.TODO(), &policy); err != nil {
-				errMsg := fmt.Sprintf("Error creating VerticalPodAutoscalerController networkpolicy %v: %v", policy.Name, err)
-				r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedCreate", "Create", "%s", errMsg)
-				klog.Error(errMsg)
-
-				return reconcile.Result{}, err
-			}
+	for _, params := range OperandParams(vpa) {
+		// The PodDisruptionBudgets that are no longer expected are pruned below.
+		pdb := r.PodDisruptionBudget(vpa, params)
+		if pdb == nil {
+			continue
+		}
+		result, err := r.applyObject(ctx, vpa, pdb)
+		if err != nil {
+			failures.add(reconcileFailure{
+				Operand: params.AppName, Kind: "PodDisruptionBudget", Name: pdb.Name, Reason: "FailedApply",
+				Message: fmt.Sprintf("Error applying VerticalPodAutoscalerController poddisruptionbudget %s: %v", pdb.Name, err),
+			})
+			continue
+		}
+		r.recordApply(vpaRef, "PodDisruptionBudget", pdb.Name, result)
+	}
 
-			msg := fmt.Sprintf("Created VerticalPodAutoscalerController networkpolicy: %s", policy.Name)
-			r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeNormal, "SuccessfulCreate", "Create", "%s", msg)
-			klog.Info(msg)
-		} else {
-			if equality.Semantic.DeepEqual(policy.Spec, oldpolicy.Spec) {
-				continue
-			}
-			if err := r.Update(context.TODO(), &policy); err != nil {
-				errMsg := fmt.Sprintf("Error updating VerticalPodAutoscalerController networkpolicy %s: %v", policy.Name, err)
-				r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedUpdate", "Update", "%s", errMsg)
-				klog.Error(errMsg)
+	// The objects that are no longer rendered are only reported in the status
+	// while pruning is a dry run.
+	if vpa.Spec.Pruning != autoscalingv1.PruningDryRun {
+		r.pruneStaleObjects(ctx, vpa, vpaRef, &failures)
+	}
 
-				return reconcile.Result{}, err
-			} else {
-				msg := fmt.Sprintf("Updated VerticalPodAutoscalerController networkpolicy: %s", policy.Name)
-				r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeNormal, "SuccessfulUpdate", "Update", "%s", msg)
-				klog.Info(msg)
-			}
-		}
+	// The failures are reported in a single event, and retried with an
+	// exponential backoff.
+	if len(failures) > 0 {
+		msg := fmt.Sprintf("Failed to reconcile %s", strings.Join(failures.resources(), ", "))
+		r.Recorder.Eventf(vpaRef, nil, corev1.EventTypeWarning, "FailedReconcile", "Reconcile", "%s", msg)
+		return reconcile.Result{}, failures.err()
 	}
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: debugSessionRemaining}, nil
 }
 
 // syncTLSProfile fetches the current cluster TLS profile and updates the config.
@@ -437,7 +554,7 @@ func (r *VerticalPodAutoscalerControllerReconciler) SetupWithManager(mgr ctrl.Ma
 		}
 	}()
 
-	apiServerToVPA := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+	toDefaultVPA := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
 		return []reconcile.Request{
 			{NamespacedName: types.NamespacedName{Name: r.Config.Name, Namespace: r.Config.Namespace}},
 		}
@@ -458,10 +575,39 @@ func (r *VerticalPodAutoscalerControllerReconciler) SetupWithManager(mgr ctrl.Ma
 				return r.NamePredicate(e.Object)
 			},
 		})).
-		Watches(&configv1.APIServer{}, apiServerToVPA).
+		Watches(&configv1.APIServer{}, toDefaultVPA).
+		// The operands ignore the existing platform namespaces by name, which only
+		// change as namespaces with a platform prefix are created or deleted
+		Watches(&corev1.Namespace{}, toDefaultVPA, builder.WithPredicates(predicate.Funcs{
+			UpdateFunc: func(event.UpdateEvent) bool { return false },
+		}, predicate.NewPredicateFuncs(func(o client.Object) bool {
+			return hasPlatformPrefix(o.GetName())
+		}))).
+		// The admission controller overwrites the namespace selector of its webhook when it
+		// registers it again
+		Watches(&admissionregistrationv1.MutatingWebhookConfiguration{}, toDefaultVPA, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			return o.GetName() == WebhookConfigName
+		}))).
+		// The service CA operator generates the webhook's serving certificate from the
+		// annotation of the webhook service, which is restored if the secret goes missing.
+		// Only the metadata of the one secret is of interest, so no secret data is cached.
+		Watches(&corev1.Secret{}, toDefaultVPA, builder.OnlyMetadata, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			return o.GetName() == WebhookCertSecretName
+		}))).
+		// Every type of object the operator applies is owned, so deleting or editing
+		// one is reverted right away.
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{}).
+		// A failed pass is retried after a delay doubling from one second up to five
+		// minutes, so a resource that keeps failing does not keep the operator busy.
+		WithOptions(controller.Options{
+			RateLimiter: workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](time.Second, 5*time.Minute),
+		}).
 		Complete(r)
 }
 
@@ -533,131 +679,83 @@ func (r *VerticalPodAutoscalerControllerReconciler) NamePredicate(meta metav1.Ob
 	return true
 }
 
-// CreateAutoscaler will create the deployment for the given
-// VerticalPodAutoscalerController custom resource instance.
-func (r *VerticalPodAutoscalerControllerReconciler) CreateAutoscaler(vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) error {
-	klog.Infof("Creating VerticalPodAutoscalerController deployment: %s", params.NameMethod(r, vpa))
-	deployment := r.AutoscalerDeployment(vpa, params)
-
-	// Set VerticalPodAutoscalerController instance as the owner and controller.
-	if err := controllerutil.SetControllerReference(vpa, deployment, r.Scheme); err != nil {
-		return err
-	}
-
-	return r.Create(context.TODO(), deployment)
+// ApplyAutoscaler server-side applies the expected deployment for the given
+// VerticalPodAutoscalerController custom resource instance, reverting any
+// change to the fields the operator renders.
+func (r *VerticalPodAutoscalerControllerReconciler) ApplyAutoscaler(ctx context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) (ApplyResult, error) {
+	return r.applyObject(ctx, vpa, r.AutoscalerDeployment(vpa, params))
 }
 
-// UpdateAutoscaler will retrieve the deployment for the given VerticalPodAutoscalerController
-// custom resource instance and update it to match the expected spec if needed.
-func (r *VerticalPodAutoscalerControllerReconciler) UpdateAutoscaler(vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) (updated bool, err error) {
-	existingDeployment := &appsv1.Deployment{}
-	err = r.Get(context.TODO(), params.NameMethod(r, vpa), existingDeployment)
-	if err != nil {
-		return false, err
-	}
-
-	existingSpec := &existingDeployment.Spec.Template.Spec
-	expectedSpec := params.PodSpecMethod(r, vpa, params)
-	expectedReplicas := int32(1)
-	// disable the controller if it shouldn't be enabled
-	if !params.EnabledMethod(r, vpa) {
-		expectedReplicas = 0
+// DeleteWebhookConfig deletes the mutating webhook configuration registered
+// by the admission controller. It returns whether there was one to delete.
+func (r *VerticalPodAutoscalerControllerReconciler) DeleteWebhookConfig(ctx context.Context) (bool, error) {
+	config := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	if err := r.Get(ctx, types.NamespacedName{Name: WebhookConfigName}, config); err != nil {
+		return false, client.IgnoreNotFound(err)
 	}
-
-	// Only comparing podSpec, replicas and release version for now.
-	if equality.Semantic.DeepEqual(existingSpec, expectedSpec) &&
-		equality.Semantic.DeepEqual(existingDeployment.Spec.Replicas, &expectedReplicas) &&
-		util.ReleaseVersionMatches(existingDeployment, r.Config.ReleaseVersion) {
-		return false, err
+	if err := r.Delete(ctx, config); err != nil {
+		return false, client.IgnoreNotFound(err)
 	}
-
-	existingDeployment.Spec.Template.Spec = *expectedSpec
-	existingDeployment.Spec.Replicas = &expectedReplicas
-
-	r.UpdateAnnotations(existingDeployment)
-	r.UpdateAnnotations(&existingDeployment.Spec.Template)
-	err = r.Update(context.TODO(), existingDeployment)
-	return err == nil, err
+	return true, nil
 }
 
-// CreateWebhookService will create the webhook service for the given
-// VerticalPodAutoscalerController custom resource instance.
-func (r *VerticalPodAutoscalerControllerReconciler) CreateWebhookService(vpa *autoscalingv1.VerticalPodAutoscalerController) error {
-	klog.Infof("Creating VerticalPodAutoscalerController service: %s", WebhookServiceName)
-	service := r.WebhookService(vpa)
-
-	// Set VerticalPodAutoscalerController instance as the owner and controller.
-	if err := controllerutil.SetControllerReference(vpa, service, r.Scheme); err != nil {
-		return err
+// UpdateWebhookNamespaceScope server-side applies the namespace selector of
+// the webhooks registered by the admission controller of the given
+// VerticalPodAutoscalerController, along with the match condition excluding
+// the platform namespaces by prefix, leaving the rest of its webhook
+// configuration to the admission controller.  It returns whether it was
+// updated, which it is not until the admission controller has registered its
+// webhooks.
+func (r *VerticalPodAutoscalerControllerReconciler) UpdateWebhookNamespaceScope(ctx context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController) (bool, error) {
+	config := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	if err := r.Get(ctx, types.NamespacedName{Name: WebhookConfigName}, config); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	selector := labelSelectorApplyConfiguration(WebhookNamespaceSelector(vpa, r.Config))
+	condition := admissionregistrationv1ac.MatchCondition().
+		WithName(WebhookPlatformNamespacesCondition).
+		WithExpression(webhookPlatformNamespacesExpression())
+	applied := admissionregistrationv1ac.MutatingWebhookConfiguration(WebhookConfigName)
+	for _, webhook := range config.Webhooks {
+		applied.WithWebhooks(admissionregistrationv1ac.MutatingWebhook().
+			WithName(webhook.Name).
+			WithNamespaceSelector(selector).
+			WithMatchConditions(condition))
+	}
+	if err := r.Apply(ctx, applied, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return false, err
 	}
-
-	return r.Create(context.TODO(), service)
+	return applied.ResourceVersion != nil && *applied.ResourceVersion != config.ResourceVersion, nil
 }
 
-// UpdateWebhookService will retrieve the service for the given VerticalPodAutoscalerController
-// custom resource instance and update it to match the expected spec if needed.
-func (r *VerticalPodAutoscalerControllerReconciler) UpdateWebhookService(vpa *autoscalingv1.VerticalPodAutoscalerController) (updated bool, err error) {
-
-	nn := types.NamespacedName{
-		Name:      WebhookServiceName,
-		Namespace: r.Config.Namespace,
-	}
-	existingService := &corev1.Service{}
-	err = r.Get(context.TODO(), nn, existingService)
-	if err != nil {
-		return false, err
+// labelSelectorApplyConfiguration returns the apply configuration setting
+// the given label selector.
+func labelSelectorApplyConfiguration(selector *metav1.LabelSelector) *metav1ac.LabelSelectorApplyConfiguration {
+	applied := metav1ac.LabelSelector()
+	if len(selector.MatchLabels) > 0 {
+		applied.WithMatchLabels(selector.MatchLabels)
 	}
-
-	merged := existingService.DeepCopy()
-	expected := r.WebhookService(vpa)
-	// Only comparing service spec.ports, spec.selector, and annotations (including release version)
-	merged.Spec.Ports = expected.Spec.Ports
-	merged.Spec.Selector = expected.Spec.Selector
-	r.UpdateServiceAnnotations(merged)
-	if equality.Semantic.DeepEqual(existingService, merged) {
-		return false, nil
+	for _, requirement := range selector.MatchExpressions {
+		applied.WithMatchExpressions(metav1ac.LabelSelectorRequirement().
+			WithKey(requirement.Key).
+			WithOperator(requirement.Operator).
+			WithValues(requirement.Values...))
 	}
-
-	err = r.Update(context.TODO(), merged)
-	return err == nil, err
+	return applied
 }
 
-// CreateCAConfigMap will create the CA ConfigMap for the given
-// VerticalPodAutoscalerController custom resource instance.
-func (r *VerticalPodAutoscalerControllerReconciler) CreateCAConfigMap(vpa *autoscalingv1.VerticalPodAutoscalerController) error {
-	klog.Infof("Creating VerticalPodAutoscalerController configmap: %s", CACertConfigMapName)
-	cm := r.CAConfigMap(vpa)
-
-	// Set VerticalPodAutoscalerController instance as the owner and controller.
-	if err := controllerutil.SetControllerReference(vpa, cm, r.Scheme); err != nil {
-		return err
-	}
-
-	return r.Create(context.TODO(), cm)
+// ApplyWebhookService server-side applies the expected webhook service for
+// the given VerticalPodAutoscalerController custom resource instance.
+func (r *VerticalPodAutoscalerControllerReconciler) ApplyWebhookService(ctx context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController) (ApplyResult, error) {
+	return r.applyObject(ctx, vpa, r.WebhookService(vpa))
 }
 
-// UpdateCAConfigMap will retrieve the CA ConfigMap for the given VerticalPodAutoscalerController
-// custom resource instance and update it to match the expected spec if needed.
-func (r *VerticalPodAutoscalerControllerReconciler) UpdateCAConfigMap(vpa *autoscalingv1.VerticalPodAutoscalerController) (updated bool, err error) {
-
-	nn := types.NamespacedName{
-		Name:      CACertConfigMapName,
-		Namespace: r.Config.Namespace,
-	}
-	existingCM := &corev1.ConfigMap{}
-	err = r.Get(context.TODO(), nn, existingCM)
-	if err != nil {
-		return false, err
-	}
-
-	merged := existingCM.DeepCopy()
-	// Only comparing annotations (including release version)
-	r.UpdateConfigMapAnnotations(merged)
-	if equality.Semantic.DeepEqual(existingCM, merged) {
-		return false, nil
-	}
-	err = r.Update(context.TODO(), merged)
-	return err == nil, err
+// ApplyCAConfigMap server-side applies the expected CA ConfigMap for the
+// given VerticalPodAutoscalerController custom resource instance.  The CA
+// bundle injected into it is owned by the service CA operator.
+func (r *VerticalPodAutoscalerControllerReconciler) ApplyCAConfigMap(ctx context.Context, vpa *autoscalingv1.VerticalPodAutoscalerController) (ApplyResult, error) {
+	return r.applyObject(ctx, vpa, r.CAConfigMap(vpa))
 }
 
 // RecommenderName returns the expected NamespacedName for the deployment
@@ -687,19 +785,47 @@ func (r *VerticalPodAutoscalerControllerReconciler) AdmissionPluginName(vpa *aut
 	}
 }
 
+// RecommenderOverride returns the deployment overrides of the recommender.
+func RecommenderOverride(vpa *autoscalingv1.VerticalPodAutoscalerController) *autoscalingv1.DeploymentOverride {
+	return &vpa.Spec.DeploymentOverrides.Recommender
+}
+
+// UpdaterOverride returns the deployment overrides of the updater.
+func UpdaterOverride(vpa *autoscalingv1.VerticalPodAutoscalerController) *autoscalingv1.DeploymentOverride {
+	return &vpa.Spec.DeploymentOverrides.Updater
+}
+
+// AdmissionPluginOverride returns the deployment overrides of the admission plugin.
+func AdmissionPluginOverride(vpa *autoscalingv1.VerticalPodAutoscalerController) *autoscalingv1.DeploymentOverride {
+	return &vpa.Spec.DeploymentOverrides.Admission
+}
+
 // RecommenderEnabled returns true if the recommender should be enabled
 func (r *VerticalPodAutoscalerControllerReconciler) RecommenderEnabled(vpa *autoscalingv1.VerticalPodAutoscalerController) bool {
 	return true
 }
 
-// UpdaterEnabled returns true if the recommender should be enabled
+// UpdaterEnabled returns true if the updater should be enabled
 func (r *VerticalPodAutoscalerControllerReconciler) UpdaterEnabled(vpa *autoscalingv1.VerticalPodAutoscalerController) bool {
-	return vpa.Spec.RecommendationOnly == nil || !*vpa.Spec.RecommendationOnly
+	return vpa.Spec.UpdaterEnabled()
 }
 
-// AdmissionPluginEnabled returns true if the recommender should be enabled
+// Replicas returns the expected number of replicas of the deployment
+// described by params, which is 0 when the operand is disabled.
+func (r *VerticalPodAutoscalerControllerReconciler) Replicas(vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) int32 {
+	// disable the controller if it shouldn't be enabled
+	if !params.EnabledMethod(r, vpa) {
+		return 0
+	}
+	if replicas := params.OverrideMethod(vpa).Replicas; replicas != nil {
+		return *replicas
+	}
+	return 1
+}
+
+// AdmissionPluginEnabled returns true if the admission plugin should be enabled.
 func (r *VerticalPodAutoscalerControllerReconciler) AdmissionPluginEnabled(vpa *autoscalingv1.VerticalPodAutoscalerController) bool {
-	return vpa.Spec.RecommendationOnly == nil || !*vpa.Spec.RecommendationOnly
+	return vpa.Spec.AdmissionEnabled()
 }
 
 // UpdateAnnotations updates the annotations on the given object to the values
@@ -760,12 +886,17 @@ func (r *VerticalPodAutoscalerControllerReconciler) AutoscalerDeployment(vpa *au
 		util.ReleaseVersionAnnotation: r.Config.ReleaseVersion,
 	}
 
+	// The user's pod labels and annotations never replace the ones set by the operator.
+	override := params.OverrideMethod(vpa)
+	podLabels := map[string]string{}
+	maps.Copy(podLabels, override.Labels)
+	maps.Copy(podLabels, labels)
+	podAnnotations := map[string]string{}
+	maps.Copy(podAnnotations, override.Annotations)
+	maps.Copy(podAnnotations, annotations)
+
 	podSpec := params.PodSpecMethod(r, vpa, params)
-	replicas := int32(1)
-	// disable the controller if it shouldn't be enabled
-	if !params.EnabledMethod(r, vpa) {
-		replicas = 0
-	}
+	replicas := r.Replicas(vpa, params)
 
 	deployment := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
@@ -775,6 +906,7 @@ func (r *VerticalPodAutoscalerControllerReconciler) AutoscalerDeployment(vpa *au
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        namespacedName.Name,
 			Namespace:   namespacedName.Namespace,
+			Labels:      labels,
 			Annotations: annotations,
 		},
 		Spec: appsv1.DeploymentSpec{
@@ -782,10 +914,18 @@ func (r *VerticalPodAutoscalerControllerReconciler) AutoscalerDeployment(vpa *au
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
+			// The default strategy, set explicitly so the operator owns it.
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{
+					MaxUnavailable: ptr.To(intstr.FromString("25%")),
+					MaxSurge:       ptr.To(intstr.FromString("25%")),
+				},
+			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      labels,
-					Annotations: annotations,
+					Labels:      podLabels,
+					Annotations: podAnnotations,
 				},
 				Spec: *podSpec,
 			},
@@ -797,24 +937,13 @@ func (r *VerticalPodAutoscalerControllerReconciler) AutoscalerDeployment(vpa *au
 
 // DefaultVPAController returns a default VerticalPodAutoscalerController instance
 func (r *VerticalPodAutoscalerControllerReconciler) DefaultVPAController() *autoscalingv1.VerticalPodAutoscalerController {
-	var smf = DefaultSafetyMarginFraction
-	var podcpu = DefaultPodMinCPUMillicores
-	var podminmem = DefaultPodMinMemoryMb
-	var recommendationOnly = DefaultRecommendationOnly
-	var minReplicas = DefaultMinReplicas
-
 	vpa := &autoscalingv1.VerticalPodAutoscalerController{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      r.Config.Name,
 			Namespace: r.Config.Namespace,
 		},
 	}
-	vpa.Name = r.Config.Name
-	vpa.Spec.SafetyMarginFraction = &smf
-	vpa.Spec.PodMinCPUMillicores = &podcpu
-	vpa.Spec.PodMinMemoryMb = &podminmem
-	vpa.Spec.RecommendationOnly = &recommendationOnly
-	vpa.Spec.MinReplicas = &minReplicas
+	SetDefaults(vpa)
 	return vpa
 }
 
@@ -861,13 +990,42 @@ func (r *VerticalPodAutoscalerControllerReconciler) getDefaultTolerations() []co
 	return tolerations
 }
 
+// OperandCommands are the commands of the operands, which identify them in
+// the extra arguments and debug sessions.
+var OperandCommands = []string{RecommenderCommand, UpdaterCommand, AdmissionControllerCommand}
+
+// ParseExtraArgs parses the given extra arguments into the arguments of
+// each operand, keyed by the operand's command.  An argument prefixed with
+// the command of an operand and a colon is only passed to that operand, and
+// any other argument, such as a URL value, is passed to every operand.
+func ParseExtraArgs(extraArgs string) (map[string][]string, error) {
+	words, err := util.SplitWords(extraArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := map[string][]string{}
+	for _, word := range words {
+		command, arg, ok := strings.Cut(word, ":")
+		if !ok || !slices.Contains(OperandCommands, command) {
+			for _, command := range OperandCommands {
+				parsed[command] = append(parsed[command], word)
+			}
+			continue
+		}
+		parsed[command] = append(parsed[command], arg)
+	}
+	return parsed, nil
+}
+
 // VPAPodSpec returns the expected podSpec for the deployment belonging
 // to the given VerticalPodAutoscalerController.
 func (r *VerticalPodAutoscalerControllerReconciler) VPAPodSpec(vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) *corev1.PodSpec {
 	args := params.GetArgs(vpa, r.Config)
 
-	if r.Config.ExtraArgs != "" {
-		args = append(args, r.Config.ExtraArgs)
+	// Extra args that cannot be parsed are reported in the status instead.
+	if extraArgs, err := ParseExtraArgs(r.Config.ExtraArgs); err == nil && len(extraArgs[params.Command]) > 0 {
+		args = util.MergeArgs(args, extraArgs[params.Command])
 	}
 	gracePeriod := int64(30)
 
@@ -921,89 +1079,163 @@ func (r *VerticalPodAutoscalerControllerReconciler) VPAPodSpec(vpa *autoscalingv
 		Tolerations:                   tolerations,
 	}
 
+	override := params.OverrideMethod(vpa)
+	applyDeploymentOverride(spec, override)
+
+	// A debug session raises the verbosity over any other configuration, until it expires
+	if session := debugSession(vpa); session != nil && session.Command == params.Command {
+		spec.Containers[0].Args = util.MergeArgs(spec.Containers[0].Args, []string{VerbosityArg.Value(session.Verbosity)})
+	}
+
+	// Spread the replicas across nodes, so draining a node leaves the others running
+	if spec.Affinity == nil && override.Replicas != nil && *override.Replicas > 1 {
+		spec.Affinity = spreadAffinity(params.AppName)
+	}
+
 	return spec
 }
 
-// RecommenderControllerPodSpec returns the expected podSpec for the Recommender Controller deployment belonging
-// to the given VerticalPodAutoscalerController.
-func (r *VerticalPodAutoscalerControllerReconciler) RecommenderControllerPodSpec(vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) *corev1.PodSpec {
-	spec := r.VPAPodSpec(vpa, params)
+// spreadAffinity returns a pod anti-affinity preferring to schedule the pods
+// of the given app on different nodes.
+func spreadAffinity(appName string) *corev1.Affinity {
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: 100,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"app": appName},
+						},
+						TopologyKey: "kubernetes.io/hostname",
+					},
+				},
+			},
+		},
+	}
+}
+
+// applyDeploymentOverride applies the user's overrides for a deployment to
+// the pod spec built by the operator.
+func applyDeploymentOverride(spec *corev1.PodSpec, override *autoscalingv1.DeploymentOverride) {
+	container := &spec.Containers[0]
 
 	// Allow the user to override the resources of the container
-	if (!reflect.DeepEqual(vpa.Spec.DeploymentOverrides.Recommender.Container.Resources, corev1.ResourceRequirements{})) {
-		spec.Containers[0].Resources = vpa.Spec.DeploymentOverrides.Recommender.Container.Resources
+	if (!reflect.DeepEqual(override.Container.Resources, corev1.ResourceRequirements{})) {
+		container.Resources = override.Container.Resources
 	}
 
-	// Append user args to our container args
-	if len(vpa.Spec.DeploymentOverrides.Recommender.Container.Args) > 0 {
-		spec.Containers[0].Args = append(spec.Containers[0].Args, vpa.Spec.DeploymentOverrides.Recommender.Container.Args...)
+	// Merge user args into our container args, replacing or removing ours
+	if len(override.Container.Args) > 0 {
+		container.Args = util.MergeArgs(container.Args, override.Container.Args)
 	}
 
-	// Replace node selector, if specified
-	if len(vpa.Spec.DeploymentOverrides.Recommender.NodeSelector) > 0 {
-		spec.NodeSelector = vpa.Spec.DeploymentOverrides.Recommender.NodeSelector
+	// Append user env vars to our container env
+	if len(override.Container.Env) > 0 {
+		container.Env = append(container.Env, override.Container.Env...)
 	}
 
-	// Replace tolerations, if specified
-	if len(vpa.Spec.DeploymentOverrides.Recommender.Tolerations) > 0 {
-		spec.Tolerations = vpa.Spec.DeploymentOverrides.Recommender.Tolerations
+	// Replace the image and its pull policy, if specified
+	if override.Container.Image != "" {
+		container.Image = override.Container.Image
 	}
-
-	return spec
-}
-
-// UpdaterControllerPodSpec returns the expected podSpec for the Updater Controller deployment belonging
-// to the given VerticalPodAutoscalerController.
-func (r *VerticalPodAutoscalerControllerReconciler) UpdaterControllerPodSpec(vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) *corev1.PodSpec {
-	spec := r.VPAPodSpec(vpa, params)
-
-	// Allow the user to override the resources of the container
-	if (!reflect.DeepEqual(vpa.Spec.DeploymentOverrides.Updater.Container.Resources, corev1.ResourceRequirements{})) {
-		spec.Containers[0].Resources = vpa.Spec.DeploymentOverrides.Updater.Container.Resources
+	if override.Container.ImagePullPolicy != "" {
+		container.ImagePullPolicy = override.Container.ImagePullPolicy
 	}
 
-	// Append user args to our container args, overrides are possible by
-	if len(vpa.Spec.DeploymentOverrides.Updater.Container.Args) > 0 {
-		spec.Containers[0].Args = append(spec.Containers[0].Args, vpa.Spec.DeploymentOverrides.Updater.Container.Args...)
+	// Append user image pull secrets to the pod
+	if len(override.ImagePullSecrets) > 0 {
+		spec.ImagePullSecrets = append(spec.ImagePullSecrets, override.ImagePullSecrets...)
 	}
 
 	// Replace node selector, if specified
-	if len(vpa.Spec.DeploymentOverrides.Updater.NodeSelector) > 0 {
-		spec.NodeSelector = vpa.Spec.DeploymentOverrides.Updater.NodeSelector
+	if len(override.NodeSelector) > 0 {
+		spec.NodeSelector = override.NodeSelector
 	}
 
 	// Replace tolerations, if specified
-	if len(vpa.Spec.DeploymentOverrides.Updater.Tolerations) > 0 {
-		spec.Tolerations = vpa.Spec.DeploymentOverrides.Updater.Tolerations
+	if len(override.Tolerations) > 0 {
+		spec.Tolerations = override.Tolerations
 	}
 
-	return spec
+	// Replace affinity, topology spread constraints and priority class, if specified
+	if override.Affinity != nil {
+		spec.Affinity = override.Affinity
+	}
+	if len(override.TopologySpreadConstraints) > 0 {
+		spec.TopologySpreadConstraints = override.TopologySpreadConstraints
+	}
+	if override.PriorityClassName != "" {
+		spec.PriorityClassName = override.PriorityClassName
+	}
 }
 
-// AdmissionControllerPodSpec returns the expected podSpec for the Admission Controller deployment belonging
+// RecommenderControllerPodSpec returns the expected podSpec for the Recommender Controller deployment belonging
 // to the given VerticalPodAutoscalerController.
-func (r *VerticalPodAutoscalerControllerReconciler) AdmissionControllerPodSpec(vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) *corev1.PodSpec {
-	spec := r.VPAPodSpec(vpa, params)
+func (r *VerticalPodAutoscalerControllerReconciler) RecommenderControllerPodSpec(vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) *corev1.PodSpec {
+	return r.recommenderPodSpec(vpa, params, vpa.Spec.Recommender)
+}
 
-	// Allow the user to override the resources of the container
-	if (!reflect.DeepEqual(vpa.Spec.DeploymentOverrides.Admission.Container.Resources, corev1.ResourceRequirements{})) {
-		spec.Containers[0].Resources = vpa.Spec.DeploymentOverrides.Admission.Container.Resources
+// recommenderPodSpec returns the expected podSpec for a recommender with the
+// given config.  A recommender that loads its history from Prometheus gets
+// the token of its service account and the service CA bundle mounted, and
+// trusts the service CA for its connections to Prometheus.
+func (r *VerticalPodAutoscalerControllerReconciler) recommenderPodSpec(vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams, c *autoscalingv1.RecommenderConfig) *corev1.PodSpec {
+	spec := r.VPAPodSpec(vpa, params)
+	if c == nil || c.History == nil {
+		return spec
 	}
 
-	// Append user args to our container args
-	if len(vpa.Spec.DeploymentOverrides.Admission.Container.Args) > 0 {
-		spec.Containers[0].Args = append(spec.Containers[0].Args, vpa.Spec.DeploymentOverrides.Admission.Container.Args...)
-	}
+	// The service CA is trusted on top of the system roots, since the address
+	// may as well be an external Prometheus with a publicly signed certificate.
+	// SSL_CERT_FILE is left unset, so the system bundle is still loaded.
+	spec.Containers[0].Env = append(spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "SSL_CERT_DIR",
+		Value: strings.Join(append(slices.Clone(SystemCertDirs), PrometheusCADir), ":"),
+	})
+	spec.Containers[0].VolumeMounts = append(spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      "prometheus-credentials",
+		MountPath: PrometheusCredentialsPath,
+		ReadOnly:  true,
+	})
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: "prometheus-credentials",
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+							Path:              "token",
+							ExpirationSeconds: ptr.To(int64(3600)),
+						},
+					},
+					{
+						ConfigMap: &corev1.ConfigMapProjection{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: CACertConfigMapName,
+							},
+							Items: []corev1.KeyToPath{
+								{Key: "service-ca.crt", Path: "service-ca/service-ca.crt"},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	return spec
+}
 
-	// Replace node selector, if specified
-	if len(vpa.Spec.DeploymentOverrides.Admission.NodeSelector) > 0 {
-		spec.NodeSelector = vpa.Spec.DeploymentOverrides.Admission.NodeSelector
-	}
+// UpdaterControllerPodSpec returns the expected podSpec for the Updater Controller deployment belonging
+// to the given VerticalPodAutoscalerController.
+func (r *VerticalPodAutoscalerControllerReconciler) UpdaterControllerPodSpec(vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) *corev1.PodSpec {
+	return r.VPAPodSpec(vpa, params)
+}
 
-	// Replace tolerations, if specified
-	if len(vpa.Spec.DeploymentOverrides.Admission.Tolerations) > 0 {
-		spec.Tolerations = vpa.Spec.DeploymentOverrides.Admission.Tolerations
-	}
+// AdmissionControllerPodSpec returns the expected podSpec for the Admission Controller deployment belonging
+// to the given VerticalPodAutoscalerController.
+func (r *VerticalPodAutoscalerControllerReconciler) AdmissionControllerPodSpec(vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) *corev1.PodSpec {
+	spec := r.VPAPodSpec(vpa, params)
 
 	spec.Containers[0].Ports = append(spec.Containers[0].Ports, corev1.ContainerPort{
 		ContainerPort: int32(AdmissionWebhookPort),
@@ -1036,13 +1268,52 @@ func (r *VerticalPodAutoscalerControllerReconciler) AdmissionControllerPodSpec(v
 				LocalObjectReference: corev1.LocalObjectReference{
 					Name: CACertConfigMapName,
 				},
-				DefaultMode: &defaultMode,
 			},
 		},
 	})
 	return spec
 }
 
+// PodDisruptionBudget returns the expected PodDisruptionBudget of the
+// deployment described by params, or nil if there should be none because
+// PodDisruptionBudgets are disabled or the operand is.
+func (r *VerticalPodAutoscalerControllerReconciler) PodDisruptionBudget(vpa *autoscalingv1.VerticalPodAutoscalerController, params ControllerParams) *policyv1.PodDisruptionBudget {
+	replicas := r.Replicas(vpa, params)
+	if vpa.Spec.PodDisruptionBudgets == autoscalingv1.PodDisruptionBudgetsDisabled || replicas == 0 {
+		return nil
+	}
+
+	namespacedName := params.NameMethod(r, vpa)
+	// All but one replica must stay available, so a single replica never
+	// blocks a drain. Unhealthy pods can always be evicted.
+	minAvailable := intstr.FromInt32(replicas - 1)
+	alwaysAllow := policyv1.AlwaysAllow
+
+	return &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "policy/v1",
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      namespacedName.Name,
+			Namespace: namespacedName.Namespace,
+			Annotations: map[string]string{
+				util.ReleaseVersionAnnotation: r.Config.ReleaseVersion,
+			},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"vertical-pod-autoscaler": vpa.Name,
+					"app":                     params.AppName,
+				},
+			},
+			UnhealthyPodEvictionPolicy: &alwaysAllow,
+		},
+	}
+}
+
 // WebhookService returns the expected service belonging to the given
 // VerticalPodAutoscalerController.
 func (r *VerticalPodAutoscalerControllerReconciler) WebhookService(vpa *autoscalingv1.VerticalPodAutoscalerController) *corev1.Service {
@@ -1225,27 +1496,59 @@ func (r *VerticalPodAutoscalerControllerReconciler) NetworkPolicies(vpa *autosca
 			},
 		},
 	})
-	// The Admission webhook needs to be reachable by the API server
+	// The Admission webhook needs to be reachable by the API server, if it runs
+	if r.AdmissionPluginEnabled(vpa) {
+		policies = append(policies, networkingv1.NetworkPolicy{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: networkingv1.SchemeGroupVersion.String(),
+				Kind:       "NetworkPolicy",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "vpa-allow-ingress-to-admission-webhook",
+				Namespace: r.Config.Namespace,
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"vertical-pod-autoscaler": vpa.Name,
+						"app":                     AdmissionControllerAppName,
+					},
+				},
+				Ingress: []networkingv1.NetworkPolicyIngressRule{
+					{
+						Ports: []networkingv1.NetworkPolicyPort{
+							makePort(&protocolTCP, intstr.FromInt32(int32(AdmissionWebhookPort)), 0),
+						},
+					},
+				},
+				PolicyTypes: []networkingv1.PolicyType{
+					networkingv1.PolicyTypeIngress,
+				},
+			},
+		})
+	}
+	// The operand pods have metrics endpoints which we don't expose, but if a cluster admin has exposed them, they'll need this to be able to keep using them
 	policies = append(policies, networkingv1.NetworkPolicy{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: networkingv1.SchemeGroupVersion.String(),
 			Kind:       "NetworkPolicy",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "vpa-allow-ingress-to-admission-webhook",
+			Name:      "vpa-allow-ingress-to-metrics",
 			Namespace: r.Config.Namespace,
 		},
 		Spec: networkingv1.NetworkPolicySpec{
 			PodSelector: metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					"vertical-pod-autoscaler": vpa.Name,
-					"app":                     AdmissionControllerAppName,
 				},
 			},
 			Ingress: []networkingv1.NetworkPolicyIngressRule{
 				{
 					Ports: []networkingv1.NetworkPolicyPort{
-						makePort(&protocolTCP, intstr.FromInt32(int32(AdmissionWebhookPort)), 0),
+						makePort(&protocolTCP, intstr.FromInt32(int32(8942)), 0),
+						makePort(&protocolTCP, intstr.FromInt32(int32(8943)), 0),
+						makePort(&protocolTCP, intstr.FromInt32(int32(8944)), 0),
 					},
 				},
 			},
@@ -1254,14 +1557,80 @@ func (r *VerticalPodAutoscalerControllerReconciler) NetworkPolicies(vpa *autosca
 			},
 		},
 	})
-	// The operand pods have metrics endpoints which we don't expose, but if a cluster admin has exposed them, they'll need this to be able to keep using them
-	policies = append(policies, networkingv1.NetworkPolicy{
+	// Recommenders loading their history from Prometheus need to reach it
+	if policy := r.prometheusNetworkPolicy(vpa); policy != nil {
+		policies = append(policies, *policy)
+	}
+	return policies
+}
+
+// prometheusNetworkPolicy returns the policy allowing the recommenders of the
+// given VerticalPodAutoscalerController that load their history from
+// Prometheus to reach it, or nil if none do.  Egress is limited to the port of
+// each address, and to the namespace of the address if it names a service.
+func (r *VerticalPodAutoscalerControllerReconciler) prometheusNetworkPolicy(vpa *autoscalingv1.VerticalPodAutoscalerController) *networkingv1.NetworkPolicy {
+	histories := map[string]*autoscalingv1.RecommenderHistory{}
+	if c := vpa.Spec.Recommender; c != nil && c.History != nil {
+		histories[controllerParams[0].AppName] = c.History
+	}
+	for _, rec := range vpa.Spec.Recommenders {
+		if rec.Config != nil && rec.Config.History != nil {
+			histories[NamedRecommenderParams(rec.Name).AppName] = rec.Config.History
+		}
+	}
+	if len(histories) == 0 {
+		return nil
+	}
+
+	protocolTCP := corev1.ProtocolTCP
+	var egress []networkingv1.NetworkPolicyEgressRule
+	for _, app := range slices.Sorted(maps.Keys(histories)) {
+		address := histories[app].Address
+		if address == "" {
+			address = DefaultPrometheusAddress
+		}
+		u, err := url.Parse(address)
+		if err != nil {
+			// The validating webhook rejects addresses that cannot be parsed.
+			continue
+		}
+		port := u.Port()
+		if port == "" {
+			port = "443"
+			if u.Scheme == "http" {
+				port = "80"
+			}
+		}
+
+		rule := networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{
+				makePort(&protocolTCP, intstr.Parse(port), 0),
+			},
+		}
+		// A service address is of the form <service>.<namespace>.svc[.<cluster domain>].
+		if labels := strings.Split(u.Hostname(), "."); len(labels) >= 3 && labels[2] == "svc" {
+			rule.To = []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"kubernetes.io/metadata.name": labels[1],
+						},
+					},
+				},
+			}
+		}
+		if !slices.ContainsFunc(egress, func(e networkingv1.NetworkPolicyEgressRule) bool { return equality.Semantic.DeepEqual(e, rule) }) {
+			egress = append(egress, rule)
+		}
+	}
+
+	return &networkingv1.NetworkPolicy{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: networkingv1.SchemeGroupVersion.String(),
 			Kind:       "NetworkPolicy",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "vpa-allow-ingress-to-metrics",
+			Name:      "vpa-allow-egress-to-prometheus",
 			Namespace: r.Config.Namespace,
 		},
 		Spec: networkingv1.NetworkPolicySpec{
@@ -1269,20 +1638,18 @@ func (r *VerticalPodAutoscalerControllerReconciler) NetworkPolicies(vpa *autosca
 				MatchLabels: map[string]string{
 					"vertical-pod-autoscaler": vpa.Name,
 				},
-			},
-			Ingress: []networkingv1.NetworkPolicyIngressRule{
-				{
-					Ports: []networkingv1.NetworkPolicyPort{
-						makePort(&protocolTCP, intstr.FromInt32(int32(8942)), 0),
-						makePort(&protocolTCP, intstr.FromInt32(int32(8943)), 0),
-						makePort(&protocolTCP, intstr.FromInt32(int32(8944)), 0),
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      "app",
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   slices.Sorted(maps.Keys(histories)),
 					},
 				},
 			},
+			Egress: egress,
 			PolicyTypes: []networkingv1.PolicyType{
-				networkingv1.PolicyTypeIngress,
+				networkingv1.PolicyTypeEgress,
 			},
 		},
-	})
-	return policies
+	}
 }
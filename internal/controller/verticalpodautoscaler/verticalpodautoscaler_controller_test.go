@@ -3,24 +3,38 @@ package verticalpodautoscaler
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	autoscalingv1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
 	"github.com/openshift/vertical-pod-autoscaler-operator/internal/util"
 	"github.com/openshift/vertical-pod-autoscaler-operator/test/helpers"
 	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/managedfields"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -35,6 +49,10 @@ var (
 	PodRecommendationMinMemoryMB     = float64(25)
 	RecommendationOnly               = false
 )
+
+// platformNamespaces are the PlatformNamespaces as rendered into arguments.
+const platformNamespaces = "default,kube-node-lease,kube-public,kube-system,openshift"
+
 var TestReconcilerConfig = &Config{
 	Name:           "test",
 	Namespace:      TestNamespace,
@@ -93,12 +111,46 @@ func TestAdmissionArgs(t *testing.T) {
 	args := AdmissionPluginArgs(vpa, &Config{Namespace: TestNamespace})
 
 	expected := []string{
-		fmt.Sprintf("--kube-api-qps=%.01f", 25.0),
-		fmt.Sprintf("--kube-api-burst=%.01f", 50.0),
+		fmt.Sprintf("--kube-api-qps=%v", DefaultKubeAPIQPS),
+		fmt.Sprintf("--kube-api-burst=%d", DefaultKubeAPIBurst),
 		"--tls-cert-file=/data/tls-certs/tls.crt",
 		"--tls-private-key=/data/tls-certs/tls.key",
 		"--client-ca-file=/data/tls-ca-certs/service-ca.crt",
 		"--webhook-timeout-seconds=10",
+		"--ignored-vpa-object-namespaces=" + platformNamespaces + "," + TestNamespace,
+	}
+
+	for _, e := range expected {
+		if !includeString(args, e) {
+			t.Fatalf("missing arg: %s from %s", e, args)
+		}
+	}
+
+	for _, unexpected := range []string{"--webhook-failure-policy-fail"} {
+		if includesStringWithPrefix(args, unexpected) {
+			t.Fatalf("found arg expected to be missing: %s", unexpected)
+		}
+	}
+}
+
+func TestAdmissionConfigArgs(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	vpa.Spec.Admission = &autoscalingv1.AdmissionConfig{
+		WebhookTimeoutSeconds: ptr.To(int32(20)),
+		FailurePolicy:         ptr.To(admissionregistrationv1.Fail),
+		KubeAPIQPS:            ptr.To(30.5),
+		KubeAPIBurst:          ptr.To(int32(60)),
+		IgnoredNamespaces:     []string{TestNamespace, "kube-system"},
+	}
+
+	args := AdmissionPluginArgs(vpa, &Config{Namespace: TestNamespace})
+
+	expected := []string{
+		"--webhook-timeout-seconds=20",
+		"--webhook-failure-policy-fail=true",
+		"--kube-api-qps=30.5",
+		"--kube-api-burst=60",
+		fmt.Sprintf("--ignored-vpa-object-namespaces=%s,%s", platformNamespaces, TestNamespace),
 	}
 
 	for _, e := range expected {
@@ -106,6 +158,12 @@ func TestAdmissionArgs(t *testing.T) {
 			t.Fatalf("missing arg: %s from %s", e, args)
 		}
 	}
+
+	for _, unexpected := range []string{"--webhook-timeout-seconds=10", "--kube-api-qps=25", "--kube-api-burst=50"} {
+		if includeString(args, unexpected) {
+			t.Fatalf("found default arg that should have been replaced: %s", unexpected)
+		}
+	}
 }
 
 func TestRecommenderArgs(t *testing.T) {
@@ -137,6 +195,546 @@ func TestRecommenderArgs(t *testing.T) {
 	}
 }
 
+func TestRecommenderConfigArgs(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	oomMinBumpUp := resource.MustParse("200Mi")
+	vpa.Spec.Recommender = &autoscalingv1.RecommenderConfig{
+		CPUHistogramDecayHalfLife:    &metav1.Duration{Duration: 12 * time.Hour},
+		MemoryHistogramDecayHalfLife: &metav1.Duration{Duration: 48 * time.Hour},
+		TargetCPUPercentile:          ptr.To(0.95),
+		TargetMemoryPercentile:       ptr.To(0.99),
+		OOMBumpUpRatio:               ptr.To(1.5),
+		OOMMinBumpUp:                 &oomMinBumpUp,
+		RecommenderInterval:          &metav1.Duration{Duration: 30 * time.Second},
+		CheckpointsGCInterval:        &metav1.Duration{Duration: 20 * time.Minute},
+		MemorySaver:                  ptr.To(true),
+	}
+
+	args := RecommenderArgs(vpa, &Config{Namespace: TestNamespace})
+
+	expected := []string{
+		"--cpu-histogram-decay-half-life=12h0m0s",
+		"--memory-histogram-decay-half-life=48h0m0s",
+		"--target-cpu-percentile=0.95",
+		"--target-memory-percentile=0.99",
+		"--oom-bump-up-ratio=1.5",
+		"--oom-min-bump-up-bytes=209715200",
+		"--recommender-interval=30s",
+		"--checkpoints-gc-interval=20m0s",
+		"--memory-saver=true",
+	}
+
+	for _, e := range expected {
+		if !includeString(args, e) {
+			t.Fatalf("missing arg: %s from %s", e, args)
+		}
+	}
+
+	vpa.Spec.Recommender = &autoscalingv1.RecommenderConfig{}
+	args = RecommenderArgs(vpa, &Config{Namespace: TestNamespace})
+
+	for _, e := range expected {
+		if includeString(args, e) {
+			t.Fatalf("found arg expected to be missing: %s", e)
+		}
+	}
+}
+
+func TestUpdaterConfigArgs(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{
+		EvictionTolerance:      ptr.To(0.25),
+		EvictionRateLimit:      ptr.To(2.5),
+		EvictionRateBurst:      ptr.To(int32(5)),
+		UpdaterInterval:        &metav1.Duration{Duration: 2 * time.Minute},
+		EvictAfterOOMThreshold: &metav1.Duration{Duration: 5 * time.Minute},
+		InRecommendationBoundsEvictionLifetimeThreshold: &metav1.Duration{Duration: 6 * time.Hour},
+	}
+
+	args := UpdaterArgs(vpa, &Config{Namespace: TestNamespace})
+
+	expected := []string{
+		"--eviction-tolerance=0.25",
+		"--eviction-rate-limit=2.5",
+		"--eviction-rate-burst=5",
+		"--updater-interval=2m0s",
+		"--evict-after-oom-threshold=5m0s",
+		"--in-recommendation-bounds-eviction-lifetime-threshold=6h0m0s",
+	}
+
+	for _, e := range expected {
+		if !includeString(args, e) {
+			t.Fatalf("missing arg: %s from %s", e, args)
+		}
+	}
+
+	vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{}
+	args = UpdaterArgs(vpa, &Config{Namespace: TestNamespace})
+
+	for _, e := range expected {
+		if includeString(args, e) {
+			t.Fatalf("found arg expected to be missing: %s", e)
+		}
+	}
+}
+
+func TestRecommenderHistory(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	vpa.Spec.Recommender = &autoscalingv1.RecommenderConfig{History: &autoscalingv1.RecommenderHistory{}}
+	vpa.Spec.Recommenders = []autoscalingv1.NamedRecommender{
+		{
+			Name: "frugal",
+			Config: &autoscalingv1.RecommenderConfig{History: &autoscalingv1.RecommenderHistory{
+				Address:        "http://prometheus.team-monitoring.svc.cluster.local:9090",
+				JobName:        "cadvisor",
+				NamespaceLabel: "kubernetes_namespace",
+				PodLabel:       "kubernetes_pod_name",
+				ContainerLabel: "name",
+			}},
+		},
+		{Name: "plain"},
+	}
+	r := newFakeReconciler(vpa)
+
+	args := RecommenderArgs(vpa, r.Config)
+	expected := []string{
+		"--storage=prometheus",
+		"--prometheus-address=https://thanos-querier.openshift-monitoring.svc:9091",
+		"--prometheus-bearer-token-file=/var/run/secrets/prometheus/token",
+		"--prometheus-cadvisor-job-name=kubelet",
+		"--pod-namespace-label=namespace",
+		"--pod-name-label=pod",
+		"--pod-label-prefix=label_",
+		`--metric-for-pod-labels=kube_pod_labels{job="kube-state-metrics"}`,
+		"--container-namespace-label=namespace",
+		"--container-pod-name-label=pod",
+		"--container-name-label=container",
+	}
+	for _, e := range expected {
+		assert.Contains(t, args, e)
+	}
+
+	args = NamedRecommenderArgs(vpa, &vpa.Spec.Recommenders[0], r.Config)
+	expected = []string{
+		"--prometheus-address=http://prometheus.team-monitoring.svc.cluster.local:9090",
+		"--prometheus-cadvisor-job-name=cadvisor",
+		"--pod-namespace-label=kubernetes_namespace",
+		"--container-pod-name-label=kubernetes_pod_name",
+		"--container-name-label=name",
+	}
+	for _, e := range expected {
+		assert.Contains(t, args, e)
+	}
+	assert.NotContains(t, NamedRecommenderArgs(vpa, &vpa.Spec.Recommenders[1], r.Config), "--storage=prometheus")
+
+	// Only the recommenders loading their history get the credentials mounted.
+	for _, tc := range []struct {
+		params  ControllerParams
+		mounted bool
+	}{
+		{controllerParams[0], true},
+		{NamedRecommenderParams("frugal"), true},
+		{NamedRecommenderParams("plain"), false},
+		{controllerParams[1], false},
+	} {
+		spec := tc.params.PodSpecMethod(r, vpa, tc.params)
+		hasVolume := slices.ContainsFunc(spec.Volumes, func(v corev1.Volume) bool { return v.Name == "prometheus-credentials" })
+		hasEnv := slices.Contains(spec.Containers[0].Env, corev1.EnvVar{Name: "SSL_CERT_DIR", Value: "/etc/ssl/certs:/etc/pki/tls/certs:" + PrometheusCADir})
+		assert.Equal(t, tc.mounted, hasVolume, "volume of %s", tc.params.AppName)
+		assert.Equal(t, tc.mounted, hasEnv, "env of %s", tc.params.AppName)
+	}
+
+	policy := r.prometheusNetworkPolicy(vpa)
+	if assert.NotNil(t, policy) {
+		assert.Equal(t, []string{"vpa-recommender", "vpa-recommender-frugal"}, policy.Spec.PodSelector.MatchExpressions[0].Values)
+		if assert.Len(t, policy.Spec.Egress, 2) {
+			assert.Equal(t, "openshift-monitoring", policy.Spec.Egress[0].To[0].NamespaceSelector.MatchLabels["kubernetes.io/metadata.name"])
+			assert.Equal(t, intstr.FromInt32(9091), *policy.Spec.Egress[0].Ports[0].Port)
+			assert.Equal(t, "team-monitoring", policy.Spec.Egress[1].To[0].NamespaceSelector.MatchLabels["kubernetes.io/metadata.name"])
+			assert.Equal(t, intstr.FromInt32(9090), *policy.Spec.Egress[1].Ports[0].Port)
+		}
+	}
+
+	vpa.Spec.Recommender = nil
+	vpa.Spec.Recommenders = nil
+	assert.Nil(t, r.prometheusNetworkPolicy(vpa))
+}
+
+func TestReconcilePrometheusNetworkPolicy(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	vpa.Spec.Recommender = &autoscalingv1.RecommenderConfig{History: &autoscalingv1.RecommenderHistory{}}
+	r := newFakeReconciler(vpa)
+	nn := types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}
+	policyName := types.NamespacedName{Name: "vpa-allow-egress-to-prometheus", Namespace: TestNamespace}
+
+	if _, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: nn}); err != nil {
+		t.Fatalf("unexpected error reconciling: %v", err)
+	}
+	assert.NoError(t, r.Get(context.TODO(), policyName, &networkingv1.NetworkPolicy{}))
+
+	// Turning the history off removes the policy again.
+	got := &autoscalingv1.VerticalPodAutoscalerController{}
+	assert.NoError(t, r.Get(context.TODO(), nn, got))
+	got.Spec.Recommender = nil
+	assert.NoError(t, r.Update(context.TODO(), got))
+	if _, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: nn}); err != nil {
+		t.Fatalf("unexpected error reconciling: %v", err)
+	}
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), policyName, &networkingv1.NetworkPolicy{})))
+}
+
+func TestFeatureGatesArgs(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	vpa.Spec.Recommender = &autoscalingv1.RecommenderConfig{FeatureGates: map[string]bool{"InPlaceOrRecreate": false}}
+	vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{FeatureGates: map[string]bool{"InPlaceOrRecreate": true, "Alpha": false}}
+	vpa.Spec.Admission = &autoscalingv1.AdmissionConfig{FeatureGates: map[string]bool{"InPlaceOrRecreate": true}}
+	vpa.Spec.DeploymentOverrides.Admission.Container.Args = []string{"--feature-gates=Beta=true"}
+	r := newFakeReconciler(vpa)
+
+	for _, tc := range []struct {
+		params   ControllerParams
+		expected string
+	}{
+		{controllerParams[0], "--feature-gates=InPlaceOrRecreate=false"},
+		{controllerParams[1], "--feature-gates=Alpha=false,InPlaceOrRecreate=true"},
+		// Gates set by override args are merged into the typed ones.
+		{controllerParams[2], "--feature-gates=Beta=true,InPlaceOrRecreate=true"},
+	} {
+		args := tc.params.PodSpecMethod(r, vpa, tc.params).Containers[0].Args
+		assert.Equal(t, []string{tc.expected}, argsWithPrefix(args, "--feature-gates"), "args of %s", tc.params.AppName)
+	}
+
+	vpa.Spec.Recommender.FeatureGates = nil
+	assert.Empty(t, argsWithPrefix(RecommenderArgs(vpa, r.Config), "--feature-gates"))
+}
+
+func TestLoggingArgs(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{Logging: &autoscalingv1.LoggingConfig{
+		Verbosity: ptr.To(int32(4)),
+		VModule:   "updater=6",
+	}}
+	vpa.Spec.Admission = &autoscalingv1.AdmissionConfig{Logging: &autoscalingv1.LoggingConfig{Format: autoscalingv1.LogFormatJSON}}
+	vpa.Spec.Recommenders = []autoscalingv1.NamedRecommender{
+		{Name: "db", Config: &autoscalingv1.RecommenderConfig{Logging: &autoscalingv1.LoggingConfig{Verbosity: ptr.To(int32(2))}}},
+	}
+	cfg := &Config{Namespace: TestNamespace, Verbosity: 1}
+
+	// The recommender keeps the operator's logging.
+	assert.Equal(t, []string{"--logtostderr", "--v=1"}, RecommenderArgs(vpa, cfg)[:2])
+	assert.Equal(t, []string{"--logtostderr", "--v=4", "--vmodule=updater=6"}, UpdaterArgs(vpa, cfg)[:3])
+	assert.Equal(t, []string{"--logging-format=json", "--v=1"}, AdmissionPluginArgs(vpa, cfg)[:2])
+	assert.Equal(t, []string{"--logtostderr", "--v=2"}, NamedRecommenderArgs(vpa, &vpa.Spec.Recommenders[0], cfg)[:2])
+}
+
+func TestReconcileLoggingRollsOutOnlyTheOperand(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	r := newFakeReconciler(vpa)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}}
+
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	// The fake client bumps the resourceVersion of applied objects even when
+	// they are unchanged, so the pod templates are compared instead.
+	templates := map[string]corev1.PodTemplateSpec{}
+	for _, params := range controllerParams {
+		deployment := &appsv1.Deployment{}
+		assert.NoError(t, r.Get(context.TODO(), params.NameMethod(r, vpa), deployment))
+		templates[params.AppName] = deployment.Spec.Template
+	}
+
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{Logging: &autoscalingv1.LoggingConfig{Verbosity: ptr.To(int32(6))}}
+	assert.NoError(t, r.Update(context.TODO(), vpa))
+
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	for _, params := range controllerParams {
+		deployment := &appsv1.Deployment{}
+		assert.NoError(t, r.Get(context.TODO(), params.NameMethod(r, vpa), deployment))
+		if params.AppName == "vpa-updater" {
+			assert.NotEqual(t, templates[params.AppName], deployment.Spec.Template)
+			assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Args, "--v=6")
+		} else {
+			assert.Equal(t, templates[params.AppName], deployment.Spec.Template, params.AppName)
+		}
+	}
+}
+
+func TestParseDebugSession(t *testing.T) {
+	session, err := ParseDebugSession("component=admission-controller, verbosity=8, ttl=1h30m")
+	assert.NoError(t, err)
+	assert.Equal(t, &DebugSession{Command: AdmissionControllerCommand, Verbosity: 8, TTL: 90 * time.Minute}, session)
+
+	for _, value := range []string{
+		"",
+		"component=updater,verbosity=6",
+		"component=vpa-updater,verbosity=6,ttl=30m",
+		"component=updater,verbosity=11,ttl=30m",
+		"component=updater,verbosity=6,ttl=0s",
+		"component=updater,verbosity=6,ttl=25h",
+		"component=updater,verbosity=6,ttl=30m,vmodule=updater=8",
+	} {
+		_, err := ParseDebugSession(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestReconcileDebugSession(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	vpa.Annotations = map[string]string{DebugSessionAnnotation: "component=updater,verbosity=8,ttl=30m"}
+	vpa.Spec.DeploymentOverrides.Updater.Container.Args = []string{"--v=4"}
+	r := newFakeReconciler(vpa)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}}
+
+	// The session raises the verbosity of the updater only, over its overrides.
+	result, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, result.RequeueAfter)
+	for _, params := range controllerParams {
+		deployment := &appsv1.Deployment{}
+		assert.NoError(t, r.Get(context.TODO(), params.NameMethod(r, vpa), deployment))
+		if params.Command == UpdaterCommand {
+			assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Args, "--v=8")
+			assert.NotContains(t, deployment.Spec.Template.Spec.Containers[0].Args, "--v=4")
+		} else {
+			assert.NotContains(t, deployment.Spec.Template.Spec.Containers[0].Args, "--v=8", params.AppName)
+		}
+	}
+
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	assert.Contains(t, vpa.Annotations, DebugSessionStartedAnnotation)
+	cond := meta.FindStatusCondition(vpa.Status.Conditions, autoscalingv1.ConditionDebugSession)
+	if assert.NotNil(t, cond) {
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Equal(t, ReasonDebugSessionActive, cond.Reason)
+	}
+
+	// A start time in the future restarts the session, so it does not outlast its ttl.
+	vpa.Annotations[DebugSessionStartedAnnotation] = time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	assert.NoError(t, r.Update(context.TODO(), vpa))
+
+	result, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, result.RequeueAfter)
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	started, ok := debugSessionStart(vpa)
+	assert.True(t, ok)
+	assert.False(t, started.After(time.Now()))
+
+	// Once the session expired, its annotations are removed and the verbosity reverted.
+	vpa.Annotations[DebugSessionStartedAnnotation] = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	assert.NoError(t, r.Update(context.TODO(), vpa))
+
+	result, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, r.Get(context.TODO(), controllerParams[1].NameMethod(r, vpa), deployment))
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Args, "--v=4")
+	assert.NotContains(t, deployment.Spec.Template.Spec.Containers[0].Args, "--v=8")
+
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	assert.NotContains(t, vpa.Annotations, DebugSessionAnnotation)
+	assert.NotContains(t, vpa.Annotations, DebugSessionStartedAnnotation)
+	assert.Nil(t, meta.FindStatusCondition(vpa.Status.Conditions, autoscalingv1.ConditionDebugSession))
+}
+
+func TestNamedRecommenderArgs(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	vpa.Spec.Recommender = &autoscalingv1.RecommenderConfig{TargetCPUPercentile: ptr.To(0.9)}
+	vpa.Spec.Recommenders = []autoscalingv1.NamedRecommender{
+		{
+			Name:               "db",
+			Config:             &autoscalingv1.RecommenderConfig{TargetCPUPercentile: ptr.To(0.99)},
+			DeploymentOverride: autoscalingv1.DeploymentOverride{Replicas: ptr.To(int32(2))},
+		},
+	}
+
+	args := NamedRecommenderArgs(vpa, &vpa.Spec.Recommenders[0], &Config{Name: vpa.Name, Namespace: TestNamespace})
+
+	expected := []string{
+		"--recommender-name=db",
+		"--target-cpu-percentile=0.99",
+		"--leader-elect-resource-name=vpa-recommender-db-lease",
+		fmt.Sprintf("--recommendation-margin-fraction=%.01f", RecommendationMarginFraction),
+	}
+	for _, e := range expected {
+		if !includeString(args, e) {
+			t.Fatalf("missing arg: %s from %s", e, args)
+		}
+	}
+	if includeString(args, "--target-cpu-percentile=0.9") {
+		t.Fatalf("the default recommender's config must not be inherited: %s", args)
+	}
+}
+
+func TestIgnoredNamespacesArgs(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	vpa.Spec.IgnoredNamespaces = []string{"team-a"}
+	vpa.Spec.Recommenders = []autoscalingv1.NamedRecommender{{Name: "db"}}
+	cfg := &Config{Name: "test", Namespace: TestNamespace}
+	expected := "--ignored-vpa-object-namespaces=" + platformNamespaces + ",team-a," + TestNamespace
+
+	assert.Contains(t, RecommenderArgs(vpa, cfg), expected)
+	assert.Contains(t, NamedRecommenderArgs(vpa, &vpa.Spec.Recommenders[0], cfg), expected)
+	assert.Contains(t, UpdaterArgs(vpa, cfg), expected)
+	assert.Contains(t, AdmissionPluginArgs(vpa, cfg), expected)
+}
+
+func TestNamespaceScope(t *testing.T) {
+	cfg := &Config{
+		Name:       "test",
+		Namespace:  TestNamespace,
+		Namespaces: []string{"kube-system", "openshift-monitoring", "sandbox", "team-a", "team-b", TestNamespace},
+	}
+
+	testCases := []struct {
+		label     string
+		mutate    func(vpa *autoscalingv1.VerticalPodAutoscalerController)
+		ignored   []string
+		covered   int32
+		admission string
+	}{
+		{
+			label:     "platform namespaces and the operator's are always ignored",
+			mutate:    func(vpa *autoscalingv1.VerticalPodAutoscalerController) {},
+			ignored:   append(strings.Split(platformNamespaces, ","), "openshift-monitoring", TestNamespace),
+			covered:   3,
+			admission: platformNamespaces + ",openshift-monitoring," + TestNamespace,
+		},
+		{
+			label: "ignored namespaces and selector",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.IgnoredNamespaces = []string{"team-b"}
+				vpa.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"vpa": "enabled"}}
+				vpa.Spec.Admission = &autoscalingv1.AdmissionConfig{IgnoredNamespaces: []string{"team-a"}}
+			},
+			ignored:   append(strings.Split(platformNamespaces, ","), "openshift-monitoring", "team-b", TestNamespace),
+			covered:   2,
+			admission: platformNamespaces + ",openshift-monitoring,team-a,team-b," + TestNamespace,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			vpa := NewVerticalPodAutoscaler()
+			tc.mutate(vpa)
+
+			ignored := IgnoredNamespaces(vpa, cfg)
+			slices.Sort(tc.ignored)
+			assert.Equal(t, tc.ignored, ignored)
+			assert.Equal(t, tc.covered, CoveredNamespaceCount(vpa, cfg))
+
+			selector := WebhookNamespaceSelector(vpa, cfg)
+			assert.Contains(t, AdmissionPluginArgs(vpa, cfg), "--ignored-vpa-object-namespaces="+tc.admission)
+			assert.Contains(t, selector.MatchExpressions, metav1.LabelSelectorRequirement{
+				Key:      corev1.LabelMetadataName,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   strings.Split(tc.admission, ","),
+			})
+			if vpa.Spec.NamespaceSelector != nil {
+				assert.Equal(t, vpa.Spec.NamespaceSelector.MatchLabels, selector.MatchLabels)
+				assert.Len(t, selector.MatchExpressions, 1)
+			}
+		})
+	}
+}
+
+func TestReconcileWebhookNamespaceSelector(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	vpa.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"vpa": "enabled"}}
+	vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{Enabled: ptr.To(false)}
+	webhookConfig := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: WebhookConfigName},
+		Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "vpa.k8s.io"}},
+	}
+	namespaces := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-new", Labels: map[string]string{"vpa": "enabled"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"vpa": "enabled"}}},
+	}
+	r := newFakeReconciler(append(namespaces, vpa, webhookConfig)...)
+	cfg := *TestReconcilerConfig
+	r.Config = &cfg
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}}
+
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+
+	assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: WebhookConfigName}, webhookConfig))
+	expected := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"vpa": "enabled"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      corev1.LabelMetadataName,
+			Operator: metav1.LabelSelectorOpNotIn,
+			Values:   append(strings.Split(platformNamespaces, ","), "openshift-new", TestNamespace),
+		}},
+	}
+	assert.Equal(t, expected, webhookConfig.Webhooks[0].NamespaceSelector)
+
+	// Platform namespaces created later are excluded by prefix.
+	expectedConditions := []admissionregistrationv1.MatchCondition{{
+		Name:       WebhookPlatformNamespacesCondition,
+		Expression: `!request.namespace.startsWith("kube-") && !request.namespace.startsWith("openshift-")`,
+	}}
+	assert.Equal(t, expectedConditions, webhookConfig.Webhooks[0].MatchConditions)
+
+	// Existing namespaces with a platform prefix are ignored by name.
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	assert.Equal(t, int32(1), vpa.Status.CoveredNamespaceCount)
+	assert.Contains(t, vpa.Status.IgnoredNamespaces, "openshift-new")
+}
+
+func TestReconcileNamespaceListFailure(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	r := newFakeReconciler(vpa, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-monitoring"}})
+	// Reconcile updates the namespaces of the config, which other tests share.
+	cfg := *TestReconcilerConfig
+	cfg.Namespaces = nil
+	r.Config = &cfg
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}}
+
+	fakeClient := r.Client.(client.WithWatch)
+	failingClient := interceptor.NewClient(fakeClient, interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if _, ok := list.(*corev1.NamespaceList); ok {
+				return fmt.Errorf("connection refused")
+			}
+			return c.List(ctx, list, opts...)
+		},
+	})
+	r.Client = failingClient
+
+	// The operands are not rolled out before the platform namespaces they
+	// ignore are known, but everything else is reconciled.
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.ErrorContains(t, err, "connection refused")
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), r.RecommenderName(vpa), &appsv1.Deployment{})))
+	assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: WebhookServiceName, Namespace: TestNamespace}, &corev1.Service{}))
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	cond := meta.FindStatusCondition(vpa.Status.Conditions, "ReconcileFailed.verticalpodautoscalercontroller."+vpa.Name)
+	if assert.NotNil(t, cond) {
+		assert.Equal(t, "FailedList", cond.Reason)
+	}
+
+	r.Client = fakeClient
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, r.Get(context.TODO(), r.RecommenderName(vpa), deployment))
+	ignored := argsWithPrefix(deployment.Spec.Template.Spec.Containers[0].Args, "--ignored-vpa-object-namespaces=")
+	if assert.Len(t, ignored, 1) {
+		assert.Contains(t, ignored[0], "openshift-monitoring")
+	}
+
+	// The namespaces listed last are kept if listing them fails again.
+	r.Client = failingClient
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.ErrorContains(t, err, "connection refused")
+	assert.Contains(t, cfg.Namespaces, "openshift-monitoring")
+	assert.NoError(t, r.Get(context.TODO(), r.RecommenderName(vpa), deployment))
+}
+
 func TestOverrideResources(t *testing.T) {
 	vpa := NewVerticalPodAutoscaler()
 	r := newFakeReconciler(vpa, &appsv1.Deployment{})
@@ -201,6 +799,154 @@ func TestOverrideArgs(t *testing.T) {
 
 }
 
+func TestOverrideArgsReplaceDefaults(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	r := newFakeReconciler(vpa)
+
+	vpa.Spec.DeploymentOverrides.Recommender.Container.Args = []string{"--v", "4", "--logtostderr-", "--memory-saver"}
+	vpa.Spec.DeploymentOverrides.Admission.Container.Args = []string{"--webhook-timeout-seconds 20"}
+
+	for _, params := range controllerParams[:] {
+		t.Run(params.AppName, func(t *testing.T) {
+			args := params.PodSpecMethod(r, vpa, params).Containers[0].Args
+			// Generating the args again yields the same argv, so it never causes a rollout.
+			assert.Equal(t, args, params.PodSpecMethod(r, vpa, params).Containers[0].Args)
+
+			switch params.AppName {
+			case "vpa-recommender":
+				assert.Equal(t, []string{"--v=4"}, argsWithPrefix(args, "--v="))
+				assert.NotContains(t, args, "--logtostderr")
+				assert.Equal(t, "--memory-saver", args[len(args)-1])
+			case AdmissionControllerAppName:
+				assert.Equal(t, []string{"--webhook-timeout-seconds=20"}, argsWithPrefix(args, "--webhook-timeout-seconds"))
+			case "vpa-updater":
+				assert.Equal(t, UpdaterArgs(vpa, r.Config), args)
+			}
+		})
+	}
+}
+
+func TestExtraArgs(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	r := newFakeReconciler(vpa)
+	cfg := *TestReconcilerConfig
+	cfg.ExtraArgs = `--kube-api-qps=50 recommender:--v=4 "updater:--min-replicas 3" admission-controller:--webhook-labels='a=b c=d'`
+	r.Config = &cfg
+	vpa.Spec.Recommenders = []autoscalingv1.NamedRecommender{{Name: "frugal"}}
+
+	testCases := []struct {
+		params   ControllerParams
+		expected []string
+		absent   []string
+	}{
+		{
+			params:   controllerParams[0],
+			expected: []string{"--kube-api-qps=50", "--v=4"},
+			absent:   []string{"--v=10", "--min-replicas=3"},
+		},
+		{
+			params:   controllerParams[1],
+			expected: []string{"--kube-api-qps=50", "--v=10", "--min-replicas=3"},
+			absent:   []string{"--v=4"},
+		},
+		{
+			params:   controllerParams[2],
+			expected: []string{"--kube-api-qps=50", "--webhook-labels=a=b c=d"},
+			absent:   []string{"--v=4"},
+		},
+		{
+			params:   NamedRecommenderParams("frugal"),
+			expected: []string{"--kube-api-qps=50", "--v=4"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.params.AppName, func(t *testing.T) {
+			args := tc.params.PodSpecMethod(r, vpa, tc.params).Containers[0].Args
+			for _, arg := range tc.expected {
+				assert.Contains(t, args, arg)
+			}
+			for _, arg := range tc.absent {
+				assert.NotContains(t, args, arg)
+			}
+		})
+	}
+}
+
+func TestParseExtraArgs(t *testing.T) {
+	testCases := []struct {
+		label     string
+		extraArgs string
+		expected  map[string][]string
+	}{
+		{
+			label:     "no prefix",
+			extraArgs: `--v=4`,
+			expected: map[string][]string{
+				RecommenderCommand:         {"--v=4"},
+				UpdaterCommand:             {"--v=4"},
+				AdmissionControllerCommand: {"--v=4"},
+			},
+		},
+		{
+			label:     "operand prefix",
+			extraArgs: `updater:--min-replicas=3`,
+			expected: map[string][]string{
+				UpdaterCommand: {"--min-replicas=3"},
+			},
+		},
+		{
+			label:     "url value",
+			extraArgs: `--prometheus-address https://prometheus:9091`,
+			expected: map[string][]string{
+				RecommenderCommand:         {"--prometheus-address", "https://prometheus:9091"},
+				UpdaterCommand:             {"--prometheus-address", "https://prometheus:9091"},
+				AdmissionControllerCommand: {"--prometheus-address", "https://prometheus:9091"},
+			},
+		},
+		{
+			label:     "prefixed url value",
+			extraArgs: `recommender:--prometheus-address recommender:https://prometheus:9091`,
+			expected: map[string][]string{
+				RecommenderCommand: {"--prometheus-address", "https://prometheus:9091"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			parsed, err := ParseExtraArgs(tc.extraArgs)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, parsed)
+		})
+	}
+}
+
+func TestExtraArgsInvalid(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	r := newFakeReconciler(vpa)
+	cfg := *TestReconcilerConfig
+	cfg.ExtraArgs = `--v=4 --kube-api-qps="50`
+	r.Config = &cfg
+
+	// Extra args that cannot be parsed are ignored as a whole.
+	for _, params := range controllerParams {
+		args := params.PodSpecMethod(r, vpa, params).Containers[0].Args
+		assert.Equal(t, params.GetArgs(vpa, r.Config), args)
+	}
+}
+
+// argsWithPrefix returns the args starting with the given prefix.
+func argsWithPrefix(args []string, prefix string) []string {
+	var matching []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, prefix) {
+			matching = append(matching, arg)
+		}
+	}
+	return matching
+}
+
 func TestOverrideNodeSelector(t *testing.T) {
 	vpa := NewVerticalPodAutoscaler()
 	r := newFakeReconciler(vpa, &appsv1.Deployment{})
@@ -243,6 +989,588 @@ func TestOverrideTolerations(t *testing.T) {
 	}
 }
 
+func TestOverridePodSpec(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	r := newFakeReconciler(vpa, &appsv1.Deployment{})
+
+	affinityOverride := &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: 100,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						TopologyKey: "kubernetes.io/hostname",
+					},
+				},
+			},
+		},
+	}
+	spreadOverride := []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       "topology.kubernetes.io/zone",
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+		},
+	}
+	envOverride := []corev1.EnvVar{{Name: "GOMEMLIMIT", Value: "200MiB"}}
+	pullSecretsOverride := []corev1.LocalObjectReference{{Name: "mirror-pull-secret"}}
+
+	for _, o := range []*autoscalingv1.DeploymentOverride{
+		&vpa.Spec.DeploymentOverrides.Admission,
+		&vpa.Spec.DeploymentOverrides.Recommender,
+		&vpa.Spec.DeploymentOverrides.Updater,
+	} {
+		o.Affinity = affinityOverride
+		o.TopologySpreadConstraints = spreadOverride
+		o.PriorityClassName = "openshift-user-critical"
+		o.Container.Env = envOverride
+		o.Container.Image = "mirror.example.com/vpa:patched"
+		o.Container.ImagePullPolicy = corev1.PullIfNotPresent
+		o.ImagePullSecrets = pullSecretsOverride
+	}
+
+	for _, params := range controllerParams {
+		t.Run(fmt.Sprintf("override %s pod spec", params.AppName), func(t *testing.T) {
+			podSpec := params.PodSpecMethod(r, vpa, params)
+			assert.Equal(t, affinityOverride, podSpec.Affinity)
+			assert.Equal(t, spreadOverride, podSpec.TopologySpreadConstraints)
+			assert.Equal(t, "openshift-user-critical", podSpec.PriorityClassName)
+			assert.Equal(t, "NAMESPACE", podSpec.Containers[0].Env[0].Name)
+			assert.Contains(t, podSpec.Containers[0].Env, envOverride[0])
+			assert.Equal(t, "mirror.example.com/vpa:patched", podSpec.Containers[0].Image)
+			assert.Equal(t, corev1.PullIfNotPresent, podSpec.Containers[0].ImagePullPolicy)
+			assert.Equal(t, pullSecretsOverride, podSpec.ImagePullSecrets)
+		})
+	}
+}
+
+func TestOverridePodMetadata(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	r := newFakeReconciler(vpa)
+
+	for _, o := range []*autoscalingv1.DeploymentOverride{
+		&vpa.Spec.DeploymentOverrides.Admission,
+		&vpa.Spec.DeploymentOverrides.Recommender,
+		&vpa.Spec.DeploymentOverrides.Updater,
+	} {
+		o.Labels = map[string]string{"sidecar.istio.io/inject": "false", "app": "other"}
+		o.Annotations = map[string]string{"cost-center": "platform"}
+	}
+
+	for _, params := range controllerParams {
+		t.Run(fmt.Sprintf("override %s pod metadata", params.AppName), func(t *testing.T) {
+			deployment := r.AutoscalerDeployment(vpa, params)
+			template := deployment.Spec.Template
+
+			assert.Equal(t, "false", template.Labels["sidecar.istio.io/inject"])
+			assert.Equal(t, params.AppName, template.Labels["app"], "operator labels must not be overridden")
+			assert.Equal(t, map[string]string{"vertical-pod-autoscaler": vpa.Name, "app": params.AppName}, deployment.Spec.Selector.MatchLabels)
+			assert.Equal(t, "platform", template.Annotations["cost-center"])
+			assert.Equal(t, TestReleaseVersion, template.Annotations[util.ReleaseVersionAnnotation])
+			assert.NotContains(t, deployment.Annotations, "cost-center")
+
+			// The overrides are restored on a deployment that lost them, while
+			// metadata added by others is kept.
+			delete(template.Labels, "sidecar.istio.io/inject")
+			template.Annotations["kubectl.kubernetes.io/restartedAt"] = "now"
+			assert.NoError(t, r.Create(context.TODO(), deployment))
+
+			result, err := r.ApplyAutoscaler(context.TODO(), vpa, params)
+			assert.NoError(t, err)
+			assert.Equal(t, controllerutil.OperationResultUpdated, result.Operation)
+
+			existing := &appsv1.Deployment{}
+			assert.NoError(t, r.Get(context.TODO(), params.NameMethod(r, vpa), existing))
+			assert.Equal(t, "false", existing.Spec.Template.Labels["sidecar.istio.io/inject"])
+			assert.Equal(t, "now", existing.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"])
+
+			result, err = r.ApplyAutoscaler(context.TODO(), vpa, params)
+			assert.NoError(t, err)
+			assert.Equal(t, controllerutil.OperationResultNone, result.Operation)
+		})
+	}
+}
+
+func TestReconcileRevertsDrift(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	r := newFakeReconciler(vpa)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}}
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	recorder := r.Recorder.(*events.FakeRecorder)
+	for len(recorder.Events) > 0 {
+		<-recorder.Events
+	}
+
+	// Hand edit the fields rendered by the operator, and add a field it does not render.
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, r.Get(context.TODO(), r.UpdaterName(vpa), deployment))
+	expectedArgs := deployment.Spec.Template.Spec.Containers[0].Args
+	deployment.Labels["app"] = "edited"
+	deployment.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	deployment.Spec.Template.Spec.Containers[0].Args = []string{"--v=10"}
+	deployment.Annotations["example.com/owner"] = "platform team"
+	assert.NoError(t, r.Update(context.TODO(), deployment, client.FieldOwner("kubectl-edit")))
+
+	// The service CA operator injects the CA bundle into the ConfigMap.
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: CACertConfigMapName, Namespace: TestNamespace}, cm))
+	cm.Data = map[string]string{"service-ca.crt": "bundle"}
+	assert.NoError(t, r.Update(context.TODO(), cm, client.FieldOwner("service-ca")))
+
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+
+	assert.NoError(t, r.Get(context.TODO(), r.UpdaterName(vpa), deployment))
+	assert.Equal(t, "vpa-updater", deployment.Labels["app"])
+	assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, deployment.Spec.Strategy.Type)
+	assert.Equal(t, expectedArgs, deployment.Spec.Template.Spec.Containers[0].Args)
+	assert.Equal(t, "platform team", deployment.Annotations["example.com/owner"])
+
+	assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: CACertConfigMapName, Namespace: TestNamespace}, cm))
+	assert.Equal(t, "bundle", cm.Data["service-ca.crt"])
+
+	var recorded []string
+	for len(recorder.Events) > 0 {
+		recorded = append(recorded, <-recorder.Events)
+	}
+	assert.Equal(t, []string{
+		fmt.Sprintf("Warning DriftReverted Reverted changes by kubectl-edit to VerticalPodAutoscalerController Deployment: %s", r.UpdaterName(vpa).Name),
+	}, recorded)
+}
+
+func TestReconcileContinuesPastFailures(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	r := newFakeReconciler(vpa)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}}
+	updaterName := r.UpdaterName(vpa)
+
+	// The updater deployment is denied, e.g. by a quota.
+	fakeClient := r.Client.(client.WithWatch)
+	r.Client = interceptor.NewClient(fakeClient, interceptor.Funcs{
+		Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+			if u, ok := obj.(interface{ GetKind() string }); ok && u.GetKind() == "Deployment" {
+				if n, ok := obj.(interface{ GetName() string }); ok && n.GetName() == updaterName.Name {
+					return fmt.Errorf("exceeded quota")
+				}
+			}
+			return c.Apply(ctx, obj, opts...)
+		},
+	})
+
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.ErrorContains(t, err, "exceeded quota")
+
+	// Everything else is reconciled all the same.
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), updaterName, &appsv1.Deployment{})))
+	assert.NoError(t, r.Get(context.TODO(), r.RecommenderName(vpa), &appsv1.Deployment{}))
+	assert.NoError(t, r.Get(context.TODO(), r.AdmissionPluginName(vpa), &appsv1.Deployment{}))
+	assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: WebhookServiceName, Namespace: TestNamespace}, &corev1.Service{}))
+	assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: CACertConfigMapName, Namespace: TestNamespace}, &corev1.ConfigMap{}))
+	policies := &networkingv1.NetworkPolicyList{}
+	assert.NoError(t, r.List(context.TODO(), policies))
+	assert.Len(t, policies.Items, len(r.NetworkPolicies(vpa)))
+
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	cond := meta.FindStatusCondition(vpa.Status.Conditions, "ReconcileFailed.deployment."+updaterName.Name)
+	if assert.NotNil(t, cond) {
+		assert.Equal(t, "FailedApply", cond.Reason)
+		assert.Contains(t, cond.Message, "exceeded quota")
+	}
+	cond = meta.FindStatusCondition(vpa.Status.Updater.Conditions, autoscalingv1.ConditionDegraded)
+	if assert.NotNil(t, cond) {
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Equal(t, "FailedApply", cond.Reason)
+	}
+
+	recorder := r.Recorder.(*events.FakeRecorder)
+	var warnings []string
+	for len(recorder.Events) > 0 {
+		if event := <-recorder.Events; strings.HasPrefix(event, corev1.EventTypeWarning) {
+			warnings = append(warnings, event)
+		}
+	}
+	assert.Equal(t, []string{
+		fmt.Sprintf("Warning FailedReconcile Failed to reconcile Deployment: %s", updaterName.Name),
+	}, warnings)
+
+	// Once the deployment applies, its condition is removed.
+	r.Client = fakeClient
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Get(context.TODO(), updaterName, &appsv1.Deployment{}))
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	assert.Nil(t, meta.FindStatusCondition(vpa.Status.Conditions, "ReconcileFailed.deployment."+updaterName.Name))
+}
+
+func TestReconcileTeardown(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	webhookConfig := &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: WebhookConfigName}}
+	r := newFakeReconciler(vpa, webhookConfig)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}}
+	webhookConfigName := types.NamespacedName{Name: WebhookConfigName}
+
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	assert.Contains(t, vpa.Finalizers, TeardownFinalizer)
+
+	// The admission controller is still running when the instance is deleted.
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, r.Get(context.TODO(), r.AdmissionPluginName(vpa), deployment))
+	deployment.Status.Replicas = 1
+	assert.NoError(t, r.Status().Update(context.TODO(), deployment))
+	assert.NoError(t, r.Delete(context.TODO(), vpa))
+
+	// It is scaled down before its webhook configuration is deleted.
+	result, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.NotZero(t, result.RequeueAfter)
+	assert.NoError(t, r.Get(context.TODO(), r.AdmissionPluginName(vpa), deployment))
+	assert.Equal(t, int32(0), *deployment.Spec.Replicas)
+	assert.NoError(t, r.Get(context.TODO(), webhookConfigName, &admissionregistrationv1.MutatingWebhookConfiguration{}))
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+
+	deployment.Status.Replicas = 0
+	assert.NoError(t, r.Status().Update(context.TODO(), deployment))
+
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), webhookConfigName, &admissionregistrationv1.MutatingWebhookConfiguration{})))
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), req.NamespacedName, vpa)))
+}
+
+func TestReplicas(t *testing.T) {
+	testCases := []struct {
+		label          string
+		replicas       *int32
+		affinity       *corev1.Affinity
+		expected       int32
+		leaderElection bool
+		spread         bool
+	}{
+		{
+			label:    "single replica by default",
+			expected: 1,
+		},
+		{
+			label:          "multiple replicas",
+			replicas:       ptr.To(int32(3)),
+			expected:       3,
+			leaderElection: true,
+			spread:         true,
+		},
+		{
+			label:          "multiple replicas with a custom affinity",
+			replicas:       ptr.To(int32(2)),
+			affinity:       &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}},
+			expected:       2,
+			leaderElection: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		vpa := NewVerticalPodAutoscaler()
+		r := newFakeReconciler(vpa)
+		for _, params := range controllerParams {
+			override := params.OverrideMethod(vpa)
+			override.Replicas = tc.replicas
+			override.Affinity = tc.affinity
+		}
+
+		for _, params := range controllerParams {
+			t.Run(fmt.Sprintf("%s for %s", tc.label, params.AppName), func(t *testing.T) {
+				deployment := r.AutoscalerDeployment(vpa, params)
+				assert.Equal(t, tc.expected, *deployment.Spec.Replicas)
+
+				podSpec := deployment.Spec.Template.Spec
+				if tc.spread {
+					assert.Equal(t, spreadAffinity(params.AppName), podSpec.Affinity)
+				} else {
+					assert.Equal(t, tc.affinity, podSpec.Affinity)
+				}
+
+				args := podSpec.Containers[0].Args
+				// The admission controllers serve the webhook side by side.
+				leaderElection := tc.leaderElection && params.AppName != AdmissionControllerAppName
+				assert.Equal(t, leaderElection, includeString(args, "--leader-elect=true"), "args: %v", args)
+				assert.Equal(t, leaderElection, includeString(args, "--leader-elect-resource-namespace="+TestNamespace), "args: %v", args)
+			})
+		}
+	}
+
+	t.Run("disabled operands have no replicas", func(t *testing.T) {
+		vpa := NewVerticalPodAutoscaler()
+		vpa.Spec.RecommendationOnly = ptr.To(true)
+		vpa.Spec.DeploymentOverrides.Updater.Replicas = ptr.To(int32(2))
+		r := newFakeReconciler(vpa)
+
+		for _, params := range controllerParams {
+			if params.AppName == "vpa-updater" {
+				assert.Equal(t, int32(0), r.Replicas(vpa, params))
+			}
+		}
+	})
+}
+
+func TestPodDisruptionBudget(t *testing.T) {
+	testCases := []struct {
+		label          string
+		mutate         func(vpa *autoscalingv1.VerticalPodAutoscalerController)
+		expectedMinAvs map[string]*int32
+	}{
+		{
+			label:  "single replicas never block drains",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {},
+			expectedMinAvs: map[string]*int32{
+				"vpa-recommender":          ptr.To(int32(0)),
+				"vpa-updater":              ptr.To(int32(0)),
+				AdmissionControllerAppName: ptr.To(int32(0)),
+			},
+		},
+		{
+			label: "sized to the replica count",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.DeploymentOverrides.Admission.Replicas = ptr.To(int32(3))
+			},
+			expectedMinAvs: map[string]*int32{
+				"vpa-recommender":          ptr.To(int32(0)),
+				"vpa-updater":              ptr.To(int32(0)),
+				AdmissionControllerAppName: ptr.To(int32(2)),
+			},
+		},
+		{
+			label: "none for disabled operands",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.RecommendationOnly = ptr.To(true)
+			},
+			expectedMinAvs: map[string]*int32{
+				"vpa-recommender": ptr.To(int32(0)),
+			},
+		},
+		{
+			label: "disabled",
+			mutate: func(vpa *autoscalingv1.VerticalPodAutoscalerController) {
+				vpa.Spec.PodDisruptionBudgets = autoscalingv1.PodDisruptionBudgetsDisabled
+			},
+			expectedMinAvs: map[string]*int32{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			vpa := NewVerticalPodAutoscaler()
+			tc.mutate(vpa)
+			r := newFakeReconciler(vpa)
+
+			for _, params := range controllerParams {
+				pdb := r.PodDisruptionBudget(vpa, params)
+				expected := tc.expectedMinAvs[params.AppName]
+				if expected == nil {
+					assert.Nil(t, pdb, params.AppName)
+					continue
+				}
+				if assert.NotNil(t, pdb, params.AppName) {
+					assert.Equal(t, int(*expected), pdb.Spec.MinAvailable.IntValue(), params.AppName)
+					assert.Equal(t, params.AppName, pdb.Spec.Selector.MatchLabels["app"])
+				}
+			}
+		})
+	}
+}
+
+func TestReconcilePodDisruptionBudgets(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	r := newFakeReconciler(vpa)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}}
+
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+
+	pdbs := &policyv1.PodDisruptionBudgetList{}
+	assert.NoError(t, r.List(context.TODO(), pdbs))
+	assert.Len(t, pdbs.Items, len(controllerParams))
+
+	// A PodDisruptionBudget not created by the operator is left alone.
+	unowned := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "unowned", Namespace: TestNamespace},
+	}
+	assert.NoError(t, r.Create(context.TODO(), unowned))
+
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	vpa.Spec.PodDisruptionBudgets = autoscalingv1.PodDisruptionBudgetsDisabled
+	assert.NoError(t, r.Update(context.TODO(), vpa))
+
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+
+	assert.NoError(t, r.List(context.TODO(), pdbs))
+	if assert.Len(t, pdbs.Items, 1) {
+		assert.Equal(t, "unowned", pdbs.Items[0].Name)
+	}
+}
+
+func TestReconcilePrunesStaleObjects(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	vpa.UID = "test-uid"
+	vpa.Spec.Pruning = autoscalingv1.PruningDryRun
+	// NetworkPolicies left behind by older releases, found by their label or, for
+	// those created before the label existed, their controller reference.
+	labelled := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "vpa-renamed", Namespace: TestNamespace, Labels: map[string]string{ManagedByLabel: vpa.Name}},
+	}
+	controlled := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "vpa-unlabelled", Namespace: TestNamespace},
+	}
+	assert.NoError(t, controllerutil.SetControllerReference(vpa, controlled, scheme.Scheme))
+	unmanaged := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: TestNamespace},
+	}
+	r := newFakeReconciler(vpa, labelled, controlled, unmanaged)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}}
+
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, r.Get(context.TODO(), r.UpdaterName(vpa), deployment))
+	assert.Equal(t, vpa.Name, deployment.Labels[ManagedByLabel])
+	assert.NotContains(t, deployment.Spec.Selector.MatchLabels, ManagedByLabel)
+
+	// A dry run only reports the stale objects.
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	assert.Equal(t, []string{"NetworkPolicy: vpa-renamed", "NetworkPolicy: vpa-unlabelled"}, vpa.Status.StaleObjects)
+	policies := &networkingv1.NetworkPolicyList{}
+	assert.NoError(t, r.List(context.TODO(), policies))
+	assert.Len(t, policies.Items, len(r.NetworkPolicies(vpa))+3)
+
+	vpa.Spec.Pruning = autoscalingv1.PruningEnabled
+	assert.NoError(t, r.Update(context.TODO(), vpa))
+
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), client.ObjectKeyFromObject(labelled), &networkingv1.NetworkPolicy{})))
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), client.ObjectKeyFromObject(controlled), &networkingv1.NetworkPolicy{})))
+	assert.NoError(t, r.Get(context.TODO(), client.ObjectKeyFromObject(unmanaged), &networkingv1.NetworkPolicy{}))
+	assert.NoError(t, r.List(context.TODO(), policies))
+	assert.Len(t, policies.Items, len(r.NetworkPolicies(vpa))+1)
+
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	assert.Empty(t, vpa.Status.StaleObjects)
+}
+
+func TestReconcileNamedRecommenders(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	vpa.Spec.Recommenders = []autoscalingv1.NamedRecommender{
+		{
+			Name: "db",
+			DeploymentOverride: autoscalingv1.DeploymentOverride{
+				Container: autoscalingv1.ContainerOverride{Args: []string{"--cpu-integer-post-processor-enabled"}},
+			},
+		},
+	}
+	r := newFakeReconciler(vpa)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}}
+	dbName := types.NamespacedName{Name: "vpa-recommender-db-test", Namespace: TestNamespace}
+
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, r.Get(context.TODO(), dbName, deployment))
+	assert.Equal(t, "vpa-recommender-db", deployment.Spec.Template.Labels["app"])
+	assert.Equal(t, "vpa-recommender", deployment.Spec.Template.Spec.ServiceAccountName)
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Args, "--recommender-name=db")
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Args, "--cpu-integer-post-processor-enabled")
+	assert.NoError(t, r.Get(context.TODO(), dbName, &policyv1.PodDisruptionBudget{}))
+
+	// The recommender is granted access to its lease, which the shipped Roles
+	// cannot list, while the updater's lease is one of theirs.
+	leaseName := types.NamespacedName{Name: "system:leader-locking-vpa-recommender-test", Namespace: TestNamespace}
+	role := &rbacv1.Role{}
+	assert.NoError(t, r.Get(context.TODO(), leaseName, role))
+	if assert.Len(t, role.Rules, 1) {
+		assert.Equal(t, []string{"vpa-recommender-db-lease"}, role.Rules[0].ResourceNames)
+	}
+	binding := &rbacv1.RoleBinding{}
+	assert.NoError(t, r.Get(context.TODO(), leaseName, binding))
+	assert.Equal(t, leaseName.Name, binding.RoleRef.Name)
+	assert.Equal(t, "vpa-recommender", binding.Subjects[0].Name)
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), types.NamespacedName{Name: "system:leader-locking-vpa-updater-test", Namespace: TestNamespace}, role)))
+
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	if assert.Len(t, vpa.Status.Recommenders, 1) {
+		assert.Equal(t, "db", vpa.Status.Recommenders[0].Name)
+		assert.NotEmpty(t, vpa.Status.Recommenders[0].Conditions)
+	}
+
+	// Removing the recommender removes its deployment and status.
+	vpa.Spec.Recommenders = nil
+	assert.NoError(t, r.Update(context.TODO(), vpa))
+
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), dbName, &appsv1.Deployment{})))
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), dbName, &policyv1.PodDisruptionBudget{})))
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), leaseName, &rbacv1.Role{})))
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), leaseName, &rbacv1.RoleBinding{})))
+	assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: "vpa-recommender-test", Namespace: TestNamespace}, &appsv1.Deployment{}))
+
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	assert.Empty(t, vpa.Status.Recommenders)
+}
+
+func TestReconcileIndependentOperands(t *testing.T) {
+	vpa := NewVerticalPodAutoscaler()
+	webhookConfig := &admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: WebhookConfigName}}
+	r := newFakeReconciler(vpa, webhookConfig)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: vpa.Name, Namespace: vpa.Namespace}}
+	updaterName := types.NamespacedName{Name: "vpa-updater-test", Namespace: TestNamespace}
+	admissionName := types.NamespacedName{Name: "vpa-admission-plugin-test", Namespace: TestNamespace}
+	webhookServiceName := types.NamespacedName{Name: WebhookServiceName, Namespace: TestNamespace}
+	ingressPolicyName := types.NamespacedName{Name: "vpa-allow-ingress-to-admission-webhook", Namespace: TestNamespace}
+
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Get(context.TODO(), updaterName, &appsv1.Deployment{}))
+	assert.NoError(t, r.Get(context.TODO(), admissionName, &appsv1.Deployment{}))
+
+	// Admission only: requests are set at pod creation without evictions.
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{Enabled: ptr.To(false)}
+	assert.NoError(t, r.Update(context.TODO(), vpa))
+
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), updaterName, &appsv1.Deployment{})))
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), updaterName, &policyv1.PodDisruptionBudget{})))
+	assert.NoError(t, r.Get(context.TODO(), admissionName, &appsv1.Deployment{}))
+	assert.NoError(t, r.Get(context.TODO(), webhookServiceName, &corev1.Service{}))
+	assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: WebhookConfigName}, &admissionregistrationv1.MutatingWebhookConfiguration{}))
+
+	// Updater without admission: the webhook and everything serving it is removed.
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	vpa.Spec.Updater = nil
+	vpa.Spec.Admission = &autoscalingv1.AdmissionConfig{Enabled: ptr.To(false)}
+	assert.NoError(t, r.Update(context.TODO(), vpa))
+
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Get(context.TODO(), updaterName, &appsv1.Deployment{}))
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), admissionName, &appsv1.Deployment{})))
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), webhookServiceName, &corev1.Service{})))
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), ingressPolicyName, &networkingv1.NetworkPolicy{})))
+	assert.True(t, errors.IsNotFound(r.Get(context.TODO(), types.NamespacedName{Name: WebhookConfigName}, &admissionregistrationv1.MutatingWebhookConfiguration{})))
+
+	assert.NoError(t, r.Get(context.TODO(), req.NamespacedName, vpa))
+	cond := meta.FindStatusCondition(vpa.Status.Admission.Conditions, autoscalingv1.ConditionAvailable)
+	if assert.NotNil(t, cond) {
+		assert.Equal(t, ReasonDisabled, cond.Reason)
+	}
+}
+
 // This test ensures we can actually get an autoscaler with fakeclient/client.
 // fakeclient.NewFakeClientWithScheme will os.Exit(1) with invalid scheme.
 func TestCanGetca(t *testing.T) {
@@ -251,7 +1579,14 @@ func TestCanGetca(t *testing.T) {
 
 // newFakeReconciler returns a new reconcile.Reconciler with a fake client
 func newFakeReconciler(initObjects ...runtime.Object) *VerticalPodAutoscalerControllerReconciler {
-	fakeClient := fakeclient.NewFakeClient(initObjects...)
+	fakeClient := fakeclient.NewClientBuilder().
+		WithRuntimeObjects(initObjects...).
+		WithStatusSubresource(&autoscalingv1.VerticalPodAutoscalerController{}).
+		// The fake client cannot apply NetworkPolicies with the client-go
+		// schema, so the schema of every object is deduced from its content.
+		WithTypeConverters(managedfields.NewDeducedTypeConverter()).
+		WithReturnManagedFields().
+		Build()
 	return &VerticalPodAutoscalerControllerReconciler{
 		Client:   fakeClient,
 		Scheme:   scheme.Scheme,
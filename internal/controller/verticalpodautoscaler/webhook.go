@@ -0,0 +1,19 @@
+package verticalpodautoscaler
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	autoscalingv1 "github.com/openshift/vertical-pod-autoscaler-operator/api/v1"
+)
+
+// +kubebuilder:webhook:path=/mutate-autoscaling-openshift-io-v1-verticalpodautoscalercontroller,mutating=true,failurePolicy=fail,sideEffects=None,groups=autoscaling.openshift.io,resources=verticalpodautoscalercontrollers,verbs=create;update,versions=v1,name=mverticalpodautoscalercontroller.autoscaling.openshift.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-autoscaling-openshift-io-v1-verticalpodautoscalercontroller,mutating=false,failurePolicy=fail,sideEffects=None,groups=autoscaling.openshift.io,resources=verticalpodautoscalercontrollers,verbs=create;update,versions=v1,name=vverticalpodautoscalercontroller.autoscaling.openshift.io,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the defaulting and validating webhooks
+// for VerticalPodAutoscalerController resources with the manager.
+func SetupWebhookWithManager(mgr ctrl.Manager, cfg *Config) error {
+	return ctrl.NewWebhookManagedBy(mgr, &autoscalingv1.VerticalPodAutoscalerController{}).
+		WithDefaulter(&Defaulter{}).
+		WithValidator(&Validator{Config: cfg}).
+		Complete()
+}
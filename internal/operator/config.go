@@ -52,17 +52,26 @@ type Config struct {
 	VerticalPodAutoscalerImage string
 
 	// VerticalPodAutoscalerVerbosity is the logging verbosity level for
-	// VerticalPodAutoscalerController deployments.
+	// VerticalPodAutoscalerController deployments whose logging config does
+	// not set one.
 	VerticalPodAutoscalerVerbosity int
 
 	// VerticalPodAutoscalerExtraArgs is a string of additional arguments
-	// passed to all VerticalPodAutoscalerController deployments.
+	// passed to all VerticalPodAutoscalerController deployments.  It is
+	// split like shell words, and arguments prefixed with an operand's
+	// command, e.g. "recommender:--v=4", are only passed to that operand.
 	//
 	// This is not exposed in the CRD.  It is only configurable via
 	// environment variable, and in a normal OpenShift install the CVO
 	// will remove it if set manually.  It is only for development and
 	// debugging purposes.
 	VerticalPodAutoscalerExtraArgs string
+
+	// EnableWebhooks controls whether the operator serves the defaulting
+	// and validating webhooks for VerticalPodAutoscalerController
+	// resources.  It is only meant to be disabled when running the
+	// operator outside of a cluster, where no serving certificate exists.
+	EnableWebhooks bool
 }
 
 // NewConfig returns a new Config object with defaults set.
@@ -73,6 +82,7 @@ func NewConfig() *Config {
 		VerticalPodAutoscalerName:      DefaultVerticalPodAutoscalerName,
 		VerticalPodAutoscalerImage:     DefaultVerticalPodAutoscalerImage,
 		VerticalPodAutoscalerVerbosity: DefaultVerticalPodAutoscalerVerbosity,
+		EnableWebhooks:                 true,
 	}
 }
 
@@ -115,5 +125,15 @@ func ConfigFromEnvironment() *Config {
 		config.VerticalPodAutoscalerExtraArgs = caExtraArgs
 	}
 
+	if enableWebhooks, ok := os.LookupEnv("ENABLE_WEBHOOKS"); ok {
+		v, err := strconv.ParseBool(enableWebhooks)
+		if err != nil {
+			v = true
+			klog.Errorf("Error parsing ENABLE_WEBHOOKS environment variable: %v", err)
+		}
+
+		config.EnableWebhooks = v
+	}
+
 	return config
 }
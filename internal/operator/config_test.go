@@ -11,4 +11,8 @@ func TestNewConfig(t *testing.T) {
 	if config.VerticalPodAutoscalerNamespace != DefaultVerticalPodAutoscalerNamespace {
 		t.Fatal("missing default for VerticalPodAutoscalerNamespace")
 	}
+
+	if !config.EnableWebhooks {
+		t.Fatal("webhooks should be enabled by default")
+	}
 }
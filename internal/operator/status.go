@@ -3,6 +3,7 @@ package operator
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -28,8 +29,51 @@ const (
 	ReasonMissingDependency = "MissingDependency"
 	ReasonSyncing           = "SyncingResources"
 	ReasonCheckAutoscaler   = "UnableToCheckAutoscalers"
+
+	ReasonRecommenderDegraded         = "RecommenderDegraded"
+	ReasonUpdaterDegraded             = "UpdaterDegraded"
+	ReasonAdmissionControllerDegraded = "AdmissionControllerDegraded"
 )
 
+// vpaController describes one of the VPA controller deployments whose status
+// is reported by the StatusReporter.
+type vpaController struct {
+	// name is the prefix of the deployment name, which is suffixed
+	// with the name of the VerticalPodAutoscalerController.
+	name string
+	// degradedReason is reported when the deployment is failing.
+	degradedReason string
+	// enabled is whether the controller runs for the given
+	// VerticalPodAutoscalerController spec.
+	enabled func(spec *autoscalingv1.VerticalPodAutoscalerControllerSpec) bool
+}
+
+// recommenderEnabled is the enabled func of the recommenders, which always run.
+func recommenderEnabled(*autoscalingv1.VerticalPodAutoscalerControllerSpec) bool {
+	return true
+}
+
+// vpaControllers are the VPA controllers checked by the StatusReporter.
+var vpaControllers = []vpaController{
+	{"vpa-recommender", ReasonRecommenderDegraded, recommenderEnabled},
+	{"vpa-updater", ReasonUpdaterDegraded, (*autoscalingv1.VerticalPodAutoscalerControllerSpec).UpdaterEnabled},
+	{"vpa-admission-plugin", ReasonAdmissionControllerDegraded, (*autoscalingv1.VerticalPodAutoscalerControllerSpec).AdmissionEnabled},
+}
+
+// OperandDegradedError is returned when one of the VPA controller deployments
+// is failing.  Its reason names the failing controller.
+type OperandDegradedError struct {
+	Reason  string
+	Message string
+}
+
+func (e *OperandDegradedError) Error() string {
+	return e.Message
+}
+
+// +kubebuilder:rbac:groups=config.openshift.io,resources=clusteroperators,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=config.openshift.io,resources=clusteroperators/status,verbs=get;update
+
 // StatusReporter reports the status of the operator to the OpenShift
 // cluster-version-operator via ClusterOperator resource status.
 type StatusReporter struct {
@@ -255,10 +299,9 @@ func (r *StatusReporter) progressing(reason, message string) error {
 	return r.ApplyStatus(status)
 }
 
-// Start checks the status of dependencies and reports the operator's status. It
-// will poll until stopCh is closed or prerequisites are satisfied, in which
-// case it will report the operator as available the configured version and wait
-// for stopCh to close before returning.
+// Start checks the status of dependencies and operands and reports the
+// operator's status.  It polls until the context is cancelled, so that
+// operands which later start failing are reported as degraded.
 func (r *StatusReporter) Start(c context.Context) error {
 	interval := 15 * time.Second
 
@@ -267,20 +310,42 @@ func (r *StatusReporter) Start(c context.Context) error {
 	// errors here should just be reported in the status message.
 	pollFunc := func(context.Context) (bool, error) {
 		// TODO(jkyros): This doesn't handle the context yet, but someday it probably should
-		return r.ReportStatus()
+		if _, err := r.ReportStatus(); err != nil {
+			klog.Errorf("Error reporting operator status: %v", err)
+		}
+		return false, nil
 	}
 
-	return wait.PollUntilContextCancel(c, interval, true, pollFunc)
+	err := wait.PollUntilContextCancel(c, interval, true, pollFunc)
+	if c.Err() != nil {
+		return nil
+	}
+
+	return err
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.  Only the
+// leader reports status, so that replicas of the operator do not race each
+// other writing the ClusterOperator.
+func (r *StatusReporter) NeedLeaderElection() bool {
+	return true
 }
 
 // ReportStatus checks the status of each dependency and operand and reports the
 // appropriate status via the operator's ClusterOperator object.
 func (r *StatusReporter) ReportStatus() (bool, error) {
-	// Check that any CluterAutoscaler deployments are updated and available.
-	ok, err := r.CheckVPARecommender()
+	// Check that the VPA controller deployments are updated and available.
+	ok, err := r.CheckVPAControllers()
 	if err != nil {
+		reason := ReasonCheckAutoscaler
 		msg := fmt.Sprintf("error checking VPA controllers status: %v", err)
-		if err := r.degraded(ReasonCheckAutoscaler, msg); err != nil {
+
+		if degradedErr, isDegraded := err.(*OperandDegradedError); isDegraded {
+			reason = degradedErr.Reason
+			msg = degradedErr.Error()
+		}
+
+		if err := r.degraded(reason, msg); err != nil {
 			return false, err
 		}
 		return false, nil
@@ -302,10 +367,14 @@ func (r *StatusReporter) ReportStatus() (bool, error) {
 	return true, nil
 }
 
-// CheckVPARecommender checks the status of any vpa-recommender
-// deployments. It returns a bool indicating whether the deployments are
-// available and fully updated to the latest version and an error.
-func (r *StatusReporter) CheckVPARecommender() (bool, error) {
+// CheckVPAControllers checks the status of the vpa-recommender, vpa-updater
+// and vpa-admission-plugin deployments, and of the deployments of any
+// alternative recommenders.  The updater and admission plugin are not checked
+// when they are disabled, either explicitly or by recommendation-only mode,
+// since they are removed.  It returns a bool indicating whether the deployments
+// are available and fully updated to the latest version and an error.  If a
+// deployment is failing, the error is an *OperandDegradedError naming it.
+func (r *StatusReporter) CheckVPAControllers() (bool, error) {
 	vpa := &autoscalingv1.VerticalPodAutoscalerController{}
 	caName := client.ObjectKey{Name: r.config.VerticalPodAutoscalerName, Namespace: r.config.VerticalPodAutoscalerNamespace}
 
@@ -319,33 +388,67 @@ func (r *StatusReporter) CheckVPARecommender() (bool, error) {
 		return false, err
 	}
 
+	controllers := slices.Clone(vpaControllers)
+	for _, rec := range vpa.Spec.Recommenders {
+		controllers = append(controllers, vpaController{"vpa-recommender-" + rec.Name, ReasonRecommenderDegraded, recommenderEnabled})
+	}
+
+	allOK := true
+	for _, c := range controllers {
+		if !c.enabled(&vpa.Spec) {
+			continue
+		}
+
+		ok, err := r.checkVPAController(c)
+		if err != nil {
+			return false, err
+		}
+
+		allOK = allOK && ok
+	}
+
+	return allOK, nil
+}
+
+// checkVPAController checks the status of the deployment of a single VPA
+// controller.  It returns a bool indicating whether the deployment is available
+// and fully updated to the latest version and an error.
+func (r *StatusReporter) checkVPAController(c vpaController) (bool, error) {
 	deployment := &appsv1.Deployment{}
 	deploymentName := client.ObjectKey{
-		Name:      fmt.Sprintf("vpa-recommender-%s", r.config.VerticalPodAutoscalerName),
+		Name:      fmt.Sprintf("%s-%s", c.name, r.config.VerticalPodAutoscalerName),
 		Namespace: r.config.VerticalPodAutoscalerNamespace,
 	}
 
 	if err := r.client.Get(context.TODO(), deploymentName, deployment); err != nil {
 		if errors.IsNotFound(err) {
-			klog.Info("No vpa-recommender deployment. Reporting unavailable.")
+			klog.Infof("No %s deployment. Reporting unavailable.", c.name)
 			return false, nil
 		}
 
-		klog.Errorf("Error getting vpa-recommender deployment: %v", err)
+		klog.Errorf("Error getting %s deployment: %v", c.name, err)
 		return false, err
 	}
 
+	if degraded, msg := util.DeploymentDegraded(deployment); degraded {
+		klog.Infof("%s deployment is degraded: %s", c.name, msg)
+		return false, &OperandDegradedError{
+			Reason:  c.degradedReason,
+			Message: fmt.Sprintf("%s deployment %s is failing: %s", c.name, deployment.Name, msg),
+		}
+	}
+
 	if !util.ReleaseVersionMatches(deployment, r.config.ReleaseVersion) {
-		klog.Info("vpa-recommender deployment version not current.")
+		klog.Infof("%s deployment version not current.", c.name)
 		return false, nil
 	}
 
 	if !util.DeploymentUpdated(deployment) {
-		klog.Info("vpa-recommender deployment updating.")
+		klog.Infof("%s deployment updating.", c.name)
 		return false, nil
 	}
 
-	klog.Info("vpa-recommender deployment is available and updated.")
+	klog.Infof("%s deployment is available and updated.", c.name)
 
 	return true, nil
 }
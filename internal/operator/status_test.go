@@ -12,6 +12,7 @@ import (
 	"github.com/openshift/vertical-pod-autoscaler-operator/internal/util"
 	"github.com/openshift/vertical-pod-autoscaler-operator/test/helpers"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -120,6 +121,32 @@ var verticalPodAutoscaler = &autoscalingv1.VerticalPodAutoscalerController{
 	},
 }
 
+// recommendationOnlyVerticalPodAutoscaler is a VerticalPodAutoscalerController
+// object in recommendation-only mode.
+var recommendationOnlyVerticalPodAutoscaler = func() *autoscalingv1.VerticalPodAutoscalerController {
+	vpa := verticalPodAutoscaler.DeepCopy()
+	recommendationOnly := true
+	vpa.Spec.RecommendationOnly = &recommendationOnly
+	return vpa
+}()
+
+// noUpdaterVerticalPodAutoscaler is a VerticalPodAutoscalerController object
+// with the updater disabled.
+var noUpdaterVerticalPodAutoscaler = func() *autoscalingv1.VerticalPodAutoscalerController {
+	vpa := verticalPodAutoscaler.DeepCopy()
+	enabled := false
+	vpa.Spec.Updater = &autoscalingv1.UpdaterConfig{Enabled: &enabled}
+	return vpa
+}()
+
+// namedRecommendersVerticalPodAutoscaler is a VerticalPodAutoscalerController
+// object with an alternative recommender.
+var namedRecommendersVerticalPodAutoscaler = func() *autoscalingv1.VerticalPodAutoscalerController {
+	vpa := verticalPodAutoscaler.DeepCopy()
+	vpa.Spec.Recommenders = []autoscalingv1.NamedRecommender{{Name: "db"}}
+	return vpa
+}()
+
 // Common Kubernetes fixture objects.
 var (
 	deployment = helpers.NewTestDeployment(&appsv1.Deployment{
@@ -140,9 +167,23 @@ var (
 			Replicas:          1,
 		},
 	})
+
+	updaterDeployment   = deployment.WithName(fmt.Sprintf("vpa-updater-%s", VerticalPodAutoscalerName))
+	admissionDeployment = deployment.WithName(fmt.Sprintf("vpa-admission-plugin-%s", VerticalPodAutoscalerName))
+	dbDeployment        = deployment.WithName(fmt.Sprintf("vpa-recommender-db-%s", VerticalPodAutoscalerName))
+
+	// crashLoopingConditions are the conditions of a deployment that has
+	// rolled out, but whose replicas never become available.
+	crashLoopingConditions = []appsv1.DeploymentCondition{
+		{
+			Type:   appsv1.DeploymentProgressing,
+			Status: corev1.ConditionTrue,
+			Reason: "NewReplicaSetAvailable",
+		},
+	}
 )
 
-func TestCheckCheckVPARecommender(t *testing.T) {
+func TestCheckVPAControllers(t *testing.T) {
 	testCases := []struct {
 		label        string
 		expectedBool bool
@@ -170,6 +211,8 @@ func TestCheckCheckVPARecommender(t *testing.T) {
 			objects: []runtime.Object{
 				verticalPodAutoscaler,
 				deployment.WithReleaseVersion("vBAD").Object(),
+				updaterDeployment.Object(),
+				admissionDeployment.Object(),
 			},
 		},
 		{
@@ -179,6 +222,101 @@ func TestCheckCheckVPARecommender(t *testing.T) {
 			objects: []runtime.Object{
 				verticalPodAutoscaler,
 				deployment.WithAvailableReplicas(0).Object(),
+				updaterDeployment.Object(),
+				admissionDeployment.Object(),
+			},
+		},
+		{
+			label:        "no updater deployment",
+			expectedBool: false,
+			expectedErr:  nil,
+			objects: []runtime.Object{
+				verticalPodAutoscaler,
+				deployment.Object(),
+				admissionDeployment.Object(),
+			},
+		},
+		{
+			label:        "admission plugin wrong version",
+			expectedBool: false,
+			expectedErr:  nil,
+			objects: []runtime.Object{
+				verticalPodAutoscaler,
+				deployment.Object(),
+				updaterDeployment.Object(),
+				admissionDeployment.WithReleaseVersion("vBAD").Object(),
+			},
+		},
+		{
+			label:        "admission plugin crash looping",
+			expectedBool: false,
+			expectedErr: &OperandDegradedError{
+				Reason:  ReasonAdmissionControllerDegraded,
+				Message: "vpa-admission-plugin deployment vpa-admission-plugin-test is failing: no replicas are available",
+			},
+			objects: []runtime.Object{
+				verticalPodAutoscaler,
+				deployment.Object(),
+				updaterDeployment.Object(),
+				admissionDeployment.WithAvailableReplicas(0).WithConditions(crashLoopingConditions).Object(),
+			},
+		},
+		{
+			label:        "recommendation only ignores updater and admission plugin",
+			expectedBool: true,
+			expectedErr:  nil,
+			objects: []runtime.Object{
+				recommendationOnlyVerticalPodAutoscaler,
+				deployment.Object(),
+				admissionDeployment.WithAvailableReplicas(0).WithConditions(crashLoopingConditions).Object(),
+			},
+		},
+		{
+			label:        "disabled updater ignored",
+			expectedBool: true,
+			expectedErr:  nil,
+			objects: []runtime.Object{
+				noUpdaterVerticalPodAutoscaler,
+				deployment.Object(),
+				admissionDeployment.Object(),
+			},
+		},
+		{
+			label:        "no alternative recommender deployment",
+			expectedBool: false,
+			expectedErr:  nil,
+			objects: []runtime.Object{
+				namedRecommendersVerticalPodAutoscaler,
+				deployment.Object(),
+				updaterDeployment.Object(),
+				admissionDeployment.Object(),
+			},
+		},
+		{
+			label:        "alternative recommender crash looping",
+			expectedBool: false,
+			expectedErr: &OperandDegradedError{
+				Reason:  ReasonRecommenderDegraded,
+				Message: "vpa-recommender-db deployment vpa-recommender-db-test is failing: no replicas are available",
+			},
+			objects: []runtime.Object{
+				namedRecommendersVerticalPodAutoscaler,
+				deployment.Object(),
+				updaterDeployment.Object(),
+				admissionDeployment.Object(),
+				dbDeployment.WithAvailableReplicas(0).WithConditions(crashLoopingConditions).Object(),
+			},
+		},
+		{
+			label:        "alternative recommender available and updated",
+			expectedBool: true,
+			expectedErr:  nil,
+			objects: []runtime.Object{
+				namedRecommendersVerticalPodAutoscaler,
+				deployment.Object(),
+				updaterDeployment.Object(),
+				admissionDeployment.Object(),
+				dbDeployment.Object(),
 			},
 		},
 		{
@@ -188,6 +326,8 @@ func TestCheckCheckVPARecommender(t *testing.T) {
 			objects: []runtime.Object{
 				verticalPodAutoscaler,
 				deployment.Object(),
+				updaterDeployment.Object(),
+				admissionDeployment.Object(),
 			},
 		},
 	}
@@ -200,7 +340,7 @@ func TestCheckCheckVPARecommender(t *testing.T) {
 				config:       &TestStatusReporterConfig,
 			}
 
-			ok, err := reporter.CheckVPARecommender()
+			ok, err := reporter.CheckVPAControllers()
 
 			if ok != tc.expectedBool {
 				t.Errorf("got %t, want %t", ok, tc.expectedBool)
@@ -280,8 +420,10 @@ func TestReportStatus(t *testing.T) {
 		expectedBool  bool
 		expectedErr   error
 		expectedConds []configv1.ClusterOperatorStatusCondition
-		clientObjs    []runtime.Object
-		configObjs    []runtime.Object
+		// expectedReason is the expected reason of the Degraded condition
+		expectedReason string
+		clientObjs     []runtime.Object
+		configObjs     []runtime.Object
 	}{
 		{
 			label:         "deployment wrong version",
@@ -292,6 +434,28 @@ func TestReportStatus(t *testing.T) {
 			clientObjs: []runtime.Object{
 				verticalPodAutoscaler,
 				deployment.WithReleaseVersion("vWRONG").Object(),
+				updaterDeployment.Object(),
+				admissionDeployment.Object(),
+			},
+		},
+		{
+			label:          "updater failing",
+			versionChange:  false,
+			expectedBool:   false,
+			expectedErr:    nil,
+			expectedConds:  DegradedConditions,
+			expectedReason: ReasonUpdaterDegraded,
+			clientObjs: []runtime.Object{
+				verticalPodAutoscaler,
+				deployment.Object(),
+				updaterDeployment.WithConditions([]appsv1.DeploymentCondition{
+					{
+						Type:    appsv1.DeploymentReplicaFailure,
+						Status:  corev1.ConditionTrue,
+						Message: "exceeded quota",
+					},
+				}).Object(),
+				admissionDeployment.Object(),
 			},
 		},
 		{
@@ -303,6 +467,8 @@ func TestReportStatus(t *testing.T) {
 			clientObjs: []runtime.Object{
 				verticalPodAutoscaler,
 				deployment.WithReleaseVersion(ReleaseVersion).Object(),
+				updaterDeployment.Object(),
+				admissionDeployment.Object(),
 			},
 		},
 	}
@@ -342,6 +508,13 @@ func TestReportStatus(t *testing.T) {
 				}
 			}
 
+			if tc.expectedReason != "" {
+				d := cvorm.FindOperatorStatusCondition(co.Status.Conditions, configv1.OperatorDegraded)
+				if d == nil || d.Reason != tc.expectedReason {
+					t.Errorf("expected Degraded condition with reason %s, got %v", tc.expectedReason, d)
+				}
+			}
+
 			// Check the LastTransitionTime of the Progressing condition.
 			for _, v := range co.Status.Versions {
 				if v.Name != "operator" {
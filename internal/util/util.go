@@ -1,13 +1,18 @@
 package util
 
 import (
+	"fmt"
+	"maps"
 	"slices"
 	"strings"
+	"unicode"
 
 	configv1 "github.com/openshift/api/config/v1"
 	cvorm "github.com/openshift/cluster-version-operator/lib/resourcemerge"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 // Common Kubernetes object annotations.
@@ -64,6 +69,33 @@ func DeploymentUpdated(dep *appsv1.Deployment) bool {
 	return true
 }
 
+// DeploymentDegraded checks whether a Kubernetes deployment object is failing
+// rather than just rolling out.  A deployment is failing if it cannot create
+// replicas, if its rollout exceeded the progress deadline, or if it finished
+// rolling out but none of its replicas are available, e.g. because they are
+// crash looping.  It returns a message describing the failure.
+func DeploymentDegraded(dep *appsv1.Deployment) (bool, string) {
+	rolledOut := false
+
+	for _, c := range dep.Status.Conditions {
+		switch {
+		case c.Type == appsv1.DeploymentReplicaFailure && c.Status == corev1.ConditionTrue:
+			return true, c.Message
+		case c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse:
+			return true, c.Message
+		case c.Type == appsv1.DeploymentProgressing && c.Reason == "NewReplicaSetAvailable":
+			rolledOut = true
+		}
+	}
+
+	if rolledOut && dep.Status.ObservedGeneration >= dep.Generation &&
+		dep.Status.Replicas > 0 && dep.Status.AvailableReplicas == 0 {
+		return true, "no replicas are available"
+	}
+
+	return false, ""
+}
+
 // ResetProgressingTime finds the Progressing condition in the given slice, or
 // creates a default one if none is found, and sets the LastTransitionTime to
 // the current time.
@@ -83,9 +115,205 @@ func ResetProgressingTime(conds *[]configv1.ClusterOperatorStatusCondition) {
 	cvorm.SetOperatorStatusCondition(conds, *prog)
 }
 
-// ArgExists checks whether a given argument exists in a slice of arguments.
-func ArgExists(args []string, arg string) bool {
-	return slices.ContainsFunc(args, func(a string) bool {
-		return strings.HasPrefix(a, arg+"=")
-	})
+// ArgName returns the name of a command line argument, without any leading
+// dashes or value, e.g. "v" for "--v=2" or "--v 2".
+func ArgName(arg string) string {
+	name, _, _ := splitArg(arg)
+	return name
+}
+
+// splitArg splits a command line argument into its name, without any leading
+// dashes, and its value, which follows the name after an equals sign or
+// whitespace.
+func splitArg(arg string) (name, value string, hasValue bool) {
+	arg = strings.TrimSpace(arg)
+	i := strings.IndexFunc(arg, func(r rune) bool { return r == '=' || unicode.IsSpace(r) })
+	if i < 0 {
+		return strings.TrimLeft(arg, "-"), "", false
+	}
+	name = strings.TrimLeft(arg[:i], "-")
+	if arg[i] == '=' {
+		return name, arg[i+1:], true
+	}
+	return name, strings.TrimSpace(arg[i:]), true
+}
+
+// Arg is a command line argument parsed by ParseArgs.
+type Arg struct {
+	// Name is the name of the flag without leading dashes, or empty if the
+	// argument is not a flag.
+	Name string
+	// Value is the value of the flag, or the whole argument if it is not a flag.
+	Value string
+	// HasValue is whether the flag has a value, rather than being a boolean
+	// flag set by its name alone.
+	HasValue bool
+	// Remove is whether the argument asks to remove the flag, which is written
+	// with a trailing dash, e.g. "--memory-saver-".
+	Remove bool
+	// Index is the index of the parsed element the argument starts at.
+	Index int
+}
+
+// String returns the argument in its canonical "--name=value" or "--name" form.
+func (a Arg) String() string {
+	switch {
+	case a.Name == "":
+		return a.Value
+	case a.HasValue:
+		return fmt.Sprintf("--%s=%s", a.Name, a.Value)
+	}
+	return "--" + a.Name
+}
+
+// ParseArgs parses the given command line arguments.  A flag may be written as
+// "--flag=value", as "--flag value" in one element or two, or as "--flag" for a
+// boolean flag, and with a single dash instead of two.  A flag with a trailing
+// dash, e.g. "--flag-", asks to remove the flag.
+func ParseArgs(args []string) []Arg {
+	var parsed []Arg
+	for i := 0; i < len(args); i++ {
+		arg := Arg{Index: i}
+		arg.Name, arg.Value, arg.HasValue = splitArg(args[i])
+		if !strings.HasPrefix(strings.TrimSpace(args[i]), "-") || arg.Name == "" {
+			parsed = append(parsed, Arg{Value: args[i], Index: i})
+			continue
+		}
+
+		if name, ok := strings.CutSuffix(arg.Name, "-"); ok {
+			arg.Name, arg.Remove = name, true
+		} else if !arg.HasValue && i+1 < len(args) && !strings.HasPrefix(strings.TrimSpace(args[i+1]), "-") {
+			arg.Value, arg.HasValue = strings.TrimSpace(args[i+1]), true
+			i++
+		}
+		parsed = append(parsed, arg)
+	}
+	return parsed
+}
+
+// MapArgs are the names of the command line arguments whose value is a comma
+// separated list of key=value pairs, such as the feature gates of the VPA.
+var MapArgs = sets.New("feature-gates")
+
+// ParseMapValue parses a comma separated list of key=value pairs.
+func ParseMapValue(value string) map[string]string {
+	pairs := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		k, v, _ := strings.Cut(pair, "=")
+		if k = strings.TrimSpace(k); k != "" {
+			pairs[k] = strings.TrimSpace(v)
+		}
+	}
+	return pairs
+}
+
+// FormatMapValue renders the given pairs as a comma separated list of
+// key=value pairs, sorted by key.
+func FormatMapValue(pairs map[string]string) string {
+	var formatted []string
+	for _, k := range slices.Sorted(maps.Keys(pairs)) {
+		formatted = append(formatted, k+"="+pairs[k])
+	}
+	return strings.Join(formatted, ",")
+}
+
+// MergeArgs returns the given default command line arguments with the given
+// overrides applied, in canonical form.  An override replaces the default flag
+// of the same name in place, or removes it if it asks to, and is appended
+// otherwise, so the result only depends on the arguments given.  Of several
+// overrides of the same flag, the last one wins.  The pairs of an override of
+// one of the MapArgs are merged into the default pair by pair instead.
+func MergeArgs(defaults, overrides []string) []string {
+	merged := ParseArgs(defaults)
+	for _, override := range ParseArgs(overrides) {
+		sameFlag := func(a Arg) bool {
+			return override.Name != "" && a.Name == override.Name
+		}
+
+		i := slices.IndexFunc(merged, sameFlag)
+		switch {
+		case override.Remove:
+			merged = slices.DeleteFunc(merged, sameFlag)
+		case i >= 0:
+			if MapArgs.Has(override.Name) && merged[i].HasValue && override.HasValue {
+				pairs := ParseMapValue(merged[i].Value)
+				maps.Copy(pairs, ParseMapValue(override.Value))
+				override.Value = FormatMapValue(pairs)
+			}
+			merged[i] = override
+			merged = slices.Concat(merged[:i+1], slices.DeleteFunc(merged[i+1:], sameFlag))
+		default:
+			merged = append(merged, override)
+		}
+	}
+
+	args := make([]string, 0, len(merged))
+	for _, arg := range merged {
+		args = append(args, arg.String())
+	}
+	return args
+}
+
+// SplitWords splits the given string into words the way a POSIX shell does,
+// without expanding anything.  Words are separated by unquoted whitespace.
+// Single quotes preserve everything up to the closing quote, double quotes
+// preserve everything but backslash escapes of '"', '\', '$' and '`', and an
+// unquoted backslash preserves the character that follows it.
+func SplitWords(s string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, c := range s {
+		switch {
+		case escaped:
+			if quote == '"' && !strings.ContainsRune("\"\\$`\n", c) {
+				word.WriteRune('\\')
+			}
+			if c != '\n' {
+				word.WriteRune(c)
+			}
+			escaped = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\\':
+			escaped, inWord = true, true
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote, inWord = c, true
+		case unicode.IsSpace(c):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+
+	switch {
+	case escaped:
+		return nil, fmt.Errorf("trailing backslash in %q", s)
+	case quote != 0:
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, s)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
 }
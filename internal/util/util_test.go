@@ -7,6 +7,7 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -180,6 +181,122 @@ func TestDeploymentUpdated(t *testing.T) {
 	}
 }
 
+func TestDeploymentDegraded(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test",
+			Namespace:  "test-namespace",
+			Generation: 100,
+		},
+	}
+
+	rolledOut := appsv1.DeploymentCondition{
+		Type:   appsv1.DeploymentProgressing,
+		Status: corev1.ConditionTrue,
+		Reason: "NewReplicaSetAvailable",
+	}
+
+	testCases := []struct {
+		label           string
+		expectedBool    bool
+		expectedMessage string
+		status          appsv1.DeploymentStatus
+	}{
+		{
+			label:        "available and updated",
+			expectedBool: false,
+			status: appsv1.DeploymentStatus{
+				AvailableReplicas:  10,
+				Replicas:           10,
+				UpdatedReplicas:    10,
+				ObservedGeneration: 100,
+				Conditions:         []appsv1.DeploymentCondition{rolledOut},
+			},
+		},
+		{
+			label:        "rolling out",
+			expectedBool: false,
+			status: appsv1.DeploymentStatus{
+				AvailableReplicas:  0,
+				Replicas:           10,
+				UpdatedReplicas:    10,
+				ObservedGeneration: 100,
+				Conditions: []appsv1.DeploymentCondition{
+					{
+						Type:   appsv1.DeploymentProgressing,
+						Status: corev1.ConditionTrue,
+						Reason: "ReplicaSetUpdated",
+					},
+				},
+			},
+		},
+		{
+			label:           "replica failure",
+			expectedBool:    true,
+			expectedMessage: "exceeded quota",
+			status: appsv1.DeploymentStatus{
+				ObservedGeneration: 100,
+				Conditions: []appsv1.DeploymentCondition{
+					{
+						Type:    appsv1.DeploymentReplicaFailure,
+						Status:  corev1.ConditionTrue,
+						Message: "exceeded quota",
+					},
+				},
+			},
+		},
+		{
+			label:           "progress deadline exceeded",
+			expectedBool:    true,
+			expectedMessage: "progress deadline exceeded",
+			status: appsv1.DeploymentStatus{
+				Replicas:           10,
+				UpdatedReplicas:    10,
+				ObservedGeneration: 100,
+				Conditions: []appsv1.DeploymentCondition{
+					{
+						Type:    appsv1.DeploymentProgressing,
+						Status:  corev1.ConditionFalse,
+						Reason:  "ProgressDeadlineExceeded",
+						Message: "progress deadline exceeded",
+					},
+				},
+			},
+		},
+		{
+			label:           "no available replicas after rollout",
+			expectedBool:    true,
+			expectedMessage: "no replicas are available",
+			status: appsv1.DeploymentStatus{
+				AvailableReplicas:  0,
+				Replicas:           10,
+				UpdatedReplicas:    10,
+				ObservedGeneration: 100,
+				Conditions:         []appsv1.DeploymentCondition{rolledOut},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			deployment.Status = tc.status
+
+			ok, msg := DeploymentDegraded(deployment)
+			if ok != tc.expectedBool {
+				t.Errorf("got %t, want %t", ok, tc.expectedBool)
+			}
+
+			if msg != tc.expectedMessage {
+				t.Errorf("got %q, want %q", msg, tc.expectedMessage)
+			}
+		})
+	}
+}
+
 func TestResetProgressingTime(t *testing.T) {
 	ConditionTransitionTime := metav1.NewTime(time.Date(
 		2009, time.November, 10, 23, 0, 0, 0, time.UTC,
@@ -230,50 +347,176 @@ func TestResetProgressingTime(t *testing.T) {
 	}
 }
 
-func TestArgExists(t *testing.T) {
+func TestArgName(t *testing.T) {
 	testCases := []struct {
 		label    string
-		args     []string
-		prefix   string
-		expected bool
+		arg      string
+		expected string
 	}{
 		{
-			label:    "argument exists",
-			args:     []string{"--kube-api-qps=25.0", "--kube-api-burst=50.0"},
-			prefix:   "--kube-api-qps",
-			expected: true,
+			label:    "double dash with value",
+			arg:      "--kube-api-qps=25.0",
+			expected: "kube-api-qps",
 		},
 		{
-			label:    "argument does not exist",
-			args:     []string{"--kube-api-qps=25.0", "--kube-api-burst=50.0"},
-			prefix:   "--kube-api-rate",
-			expected: false,
+			label:    "single dash with value",
+			arg:      "-v=4",
+			expected: "v",
 		},
 		{
-			label:    "empty arguments",
-			args:     []string{},
-			prefix:   "--kube-api-qps",
-			expected: false,
+			label:    "flag without value",
+			arg:      "--logtostderr",
+			expected: "logtostderr",
 		},
 		{
-			label:    "prefix matches partially",
-			args:     []string{"--kube-api-qps=25.0", "--kube-api-burst=50.0"},
-			prefix:   "--kube-api",
-			expected: false,
+			label:    "value separated by a space",
+			arg:      "--kube-api-qps 30",
+			expected: "kube-api-qps",
 		},
 		{
-			label:    "prefix matches partially the other way",
-			args:     []string{"--kube-api-qps=25.0", "--kube-api-burst=50.0"},
-			prefix:   "kube-api-qps",
-			expected: false,
+			label:    "value containing equals sign",
+			arg:      "--feature-gates=Foo=true",
+			expected: "feature-gates",
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.label, func(t *testing.T) {
-			result := ArgExists(tc.args, tc.prefix)
+			result := ArgName(tc.arg)
 			if result != tc.expected {
-				t.Errorf("got %t, want %t", result, tc.expected)
+				t.Errorf("got %q, want %q", result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	args := []string{"--v=2", "-kube-api-qps 30", "--leader-elect-resource-name", "vpa-lease", "--memory-saver", "--logtostderr-", "positional"}
+
+	expected := []Arg{
+		{Name: "v", Value: "2", HasValue: true, Index: 0},
+		{Name: "kube-api-qps", Value: "30", HasValue: true, Index: 1},
+		{Name: "leader-elect-resource-name", Value: "vpa-lease", HasValue: true, Index: 2},
+		{Name: "memory-saver", Index: 4},
+		{Name: "logtostderr", Remove: true, Index: 5},
+		{Value: "positional", Index: 6},
+	}
+
+	if result := ParseArgs(args); !reflect.DeepEqual(result, expected) {
+		t.Errorf("got %+v, want %+v", result, expected)
+	}
+}
+
+func TestMergeArgs(t *testing.T) {
+	defaults := []string{"--logtostderr", "--v=2", "--kube-api-qps=25", "--memory-saver=true"}
+
+	testCases := []struct {
+		label     string
+		overrides []string
+		expected  []string
+	}{
+		{
+			label:    "no overrides",
+			expected: defaults,
+		},
+		{
+			label:     "override replaces a default in place",
+			overrides: []string{"--v=4"},
+			expected:  []string{"--logtostderr", "--v=4", "--kube-api-qps=25", "--memory-saver=true"},
+		},
+		{
+			label:     "value separated by a space",
+			overrides: []string{"--kube-api-qps 30", "-v", "5"},
+			expected:  []string{"--logtostderr", "--v=5", "--kube-api-qps=30", "--memory-saver=true"},
+		},
+		{
+			label:     "boolean override",
+			overrides: []string{"--memory-saver"},
+			expected:  []string{"--logtostderr", "--v=2", "--kube-api-qps=25", "--memory-saver"},
+		},
+		{
+			label:     "removed default",
+			overrides: []string{"--memory-saver-", "--logtostderr-"},
+			expected:  []string{"--v=2", "--kube-api-qps=25"},
+		},
+		{
+			label:     "new flags are appended in order",
+			overrides: []string{"--b=1", "--a"},
+			expected:  []string{"--logtostderr", "--v=2", "--kube-api-qps=25", "--memory-saver=true", "--b=1", "--a"},
+		},
+		{
+			label:     "feature gates are merged gate by gate",
+			overrides: []string{"--feature-gates=B=true,A=false", "--feature-gates=B=false"},
+			expected:  []string{"--logtostderr", "--v=2", "--kube-api-qps=25", "--memory-saver=true", "--feature-gates=A=false,B=false"},
+		},
+		{
+			label:     "last override wins",
+			overrides: []string{"--v=4", "--b=1", "--v=6", "--b=2"},
+			expected:  []string{"--logtostderr", "--v=6", "--kube-api-qps=25", "--memory-saver=true", "--b=2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			result := MergeArgs(defaults, tc.overrides)
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("got %q, want %q", result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	testCases := []struct {
+		label    string
+		input    string
+		expected []string
+		err      bool
+	}{
+		{
+			label: "empty",
+			input: "  ",
+		},
+		{
+			label:    "whitespace separated",
+			input:    " --a=1\t--b=2\n--c ",
+			expected: []string{"--a=1", "--b=2", "--c"},
+		},
+		{
+			label:    "single quotes",
+			input:    `--msg='a "b" \c' --empty=''`,
+			expected: []string{`--msg=a "b" \c`, "--empty="},
+		},
+		{
+			label:    "double quotes",
+			input:    `--msg="a 'b' \"c\" \d"`,
+			expected: []string{`--msg=a 'b' "c" \d`},
+		},
+		{
+			label:    "escaped whitespace",
+			input:    `--msg=a\ b c`,
+			expected: []string{"--msg=a b", "c"},
+		},
+		{
+			label: "unterminated quote",
+			input: `--msg="a b`,
+			err:   true,
+		},
+		{
+			label: "trailing backslash",
+			input: `--msg=a\`,
+			err:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			result, err := SplitWords(tc.input)
+			if tc.err != (err != nil) {
+				t.Fatalf("got error %v, want error: %v", err, tc.err)
+			}
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("got %q, want %q", result, tc.expected)
 			}
 		})
 	}
@@ -35,6 +35,23 @@ func (d *TestDeployment) WithAvailableReplicas(n int32) *TestDeployment {
 	return newDeployment
 }
 
+// WithName returns a copy of the object with the name set to the given value.
+func (d *TestDeployment) WithName(n string) *TestDeployment {
+	newDeployment := d.Copy()
+	newDeployment.SetName(n)
+
+	return newDeployment
+}
+
+// WithConditions returns a copy of the object with the status conditions set
+// to the given list.
+func (d *TestDeployment) WithConditions(conds []appsv1.DeploymentCondition) *TestDeployment {
+	newDeployment := d.Copy()
+	newDeployment.Status.Conditions = conds
+
+	return newDeployment
+}
+
 // WithReleaseVersion returns a copy of the object with the release version
 // annotation set to the given value.
 func (d *TestDeployment) WithReleaseVersion(v string) *TestDeployment {